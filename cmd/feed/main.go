@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/feed"
+	"github.com/tonymj76/mytheresa-test/services"
+	"io"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// main renders the Google Merchant Center product feed to a file or stdout, e.g.
+// go run ./cmd/feed -format tsv -out products.tsv
+func main() {
+	format := flag.String("format", feed.FormatXML, "feed format, xml or tsv")
+	out := flag.String("out", "", "file to write the feed to, stdout when empty")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Warn("No .env file found, using the environment")
+	}
+
+	service, err := services.NewRestService(services.WithDBSetup())
+	if err != nil {
+		log.Fatalf("error setting up new rest server. Err: %v", err)
+	}
+	defer service.DB.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	fw, err := feed.NewWriter(*format, w, feed.ChannelFromEnv())
+	if err != nil {
+		log.Fatalf("failed to start the feed: %v", err)
+	}
+
+	report, err := feed.Render(context.Background(), service.EachProductPage, fw)
+	if err != nil {
+		log.Fatalf("failed to render the feed: %v", err)
+	}
+	for _, skipped := range report.Skipped {
+		log.WithField("id", skipped.ID).WithError(skipped.Errors).Warn("product left out of the feed")
+	}
+	log.Infof("feed written with %d items, %d skipped", report.Items, len(report.Skipped))
+}
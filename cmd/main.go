@@ -8,8 +8,10 @@ import (
 	"github.com/tonymj76/mytheresa-test/config"
 	"github.com/tonymj76/mytheresa-test/handlers"
 	"github.com/tonymj76/mytheresa-test/routes"
+	"github.com/tonymj76/mytheresa-test/rpc"
 	"github.com/tonymj76/mytheresa-test/seed"
 	"github.com/tonymj76/mytheresa-test/services"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -28,7 +30,19 @@ func init() {
 }
 
 func main() {
-	service, err := services.NewRestService(services.WithDBSetup())
+	// PRODUCT_CACHE_SIZE=0 disables the product listing cache and PRICE_STREAM_BUFFER=0 the price stream
+	service, err := services.NewRestService(
+		services.WithDBSetup(),
+		services.WithProductCache(config.GetEnvInt("PRODUCT_CACHE_SIZE", 1000),
+			time.Duration(max(config.GetEnvInt("PRODUCT_CACHE_TTL", 30), 1))*time.Second),
+		services.WithPriceFeed(config.GetEnvInt("PRICE_STREAM_BUFFER", 1000)),
+		services.WithWebhooks(services.WebhookOptions{
+			Timeout:     time.Duration(config.GetEnvInt("WEBHOOK_TIMEOUT", 10)) * time.Second,
+			MaxAttempts: config.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			Backoff:     time.Duration(config.GetEnvInt("WEBHOOK_BACKOFF", 30)) * time.Second,
+			MaxBackoff:  time.Duration(config.GetEnvInt("WEBHOOK_MAX_BACKOFF", 3600)) * time.Second,
+		}),
+	)
 	if err != nil {
 		log.Fatalf("error setting up new rest server. Err: %v", err)
 	}
@@ -37,7 +51,7 @@ func main() {
 		log.Fatalf("failed to seed database: %v", err)
 	}
 
-	handler := handlers.NewRegisteredHandler(service)
+	handler := handlers.NewRegisteredHandler(service, handlers.WithGraphQL(service.DB))
 	route := routes.SetRouter(handler)
 
 	srv := &http.Server{
@@ -53,17 +67,49 @@ func main() {
 		}
 	}()
 
+	// The metrics listen on their own port, keep it internal to the cluster
+	debugSrv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", config.GetEnv("DEBUG_PORT", "9092")),
+		Handler: routes.SetDebugRouter(),
+	}
+	go func() {
+		if err := debugSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("debug listen: %s\n", err)
+		}
+	}()
+
+	// The gRPC server listens on its own port and shares the service with the gin router
+	grpcSrv := rpc.NewServer(service, config.MaxPageSize())
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", config.GetEnv("GRPC_PORT", "9091")))
+	if err != nil {
+		log.Fatalf("grpc listen: %s\n", err)
+	}
+	go func() {
+		if err := grpcSrv.Serve(lis); err != nil {
+			log.Fatalf("grpc serve: %s\n", err)
+		}
+	}()
+
 	// Graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	<-stop
 	log.Println("Shutting down the server...")
+	// the price streams never end on their own, Shutdown would wait for them until it times out
+	service.ClosePriceFeed()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	_ = debugSrv.Shutdown(ctx)
+	grpcSrv.GracefulStop()
+
+	log.Println("Waiting for running imports...")
+	service.WaitForImports()
+	// the webhook deliveries left are sent after a restart
+	service.Close()
 
 	log.Println("Server exiting")
 }
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 // GetEnv helps to look up if the env exist or a default is provided
 func GetEnv(key, fallback string) string {
@@ -9,3 +12,18 @@ func GetEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// GetEnvInt is like GetEnv for whole numbers, the fallback is used when the env is not a valid number
+func GetEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(GetEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// MaxPageSize is the MAX_PAGE_SIZE env, the largest page the listings return. It is at least 1 since the listings
+// divide by the page size
+func MaxPageSize() int {
+	return max(GetEnvInt("MAX_PAGE_SIZE", 100), 1)
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/models"
+	"golang.org/x/text/language"
+)
+
+const (
+	// LocaleParam picks the locale of a response, it wins over the Accept-Language header
+	LocaleParam = "locale"
+	localeKey   = "locale"
+)
+
+// localeContextKey holds the locale in the context of the requests served outside of gin
+type localeContextKey struct{}
+
+// locales are the locales the catalogue is translated to, the first one is DEFAULT_LOCALE which renders the
+// untranslated names and is the fallback when the client asks for none of them
+var locales = sync.OnceValues(func() ([]language.Tag, language.Matcher) {
+	tags := []language.Tag{language.Make(GetEnv("DEFAULT_LOCALE", "en"))}
+	for _, locale := range strings.Split(GetEnv("SUPPORTED_LOCALES", "en,de,fr,it,es"), ",") {
+		tag, err := language.Parse(strings.TrimSpace(locale))
+		if err == nil && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, language.NewMatcher(tags)
+})
+
+// DefaultLocale is the locale of the untranslated names and descriptions
+func DefaultLocale() string {
+	tags, _ := locales()
+	return tags[0].String()
+}
+
+// SupportedLocale reports whether the catalogue can be translated to the locale, e.g. to validate the keys of the
+// translations
+func SupportedLocale(locale string) bool {
+	tags, _ := locales()
+	return slices.ContainsFunc(tags, func(tag language.Tag) bool {
+		return tag.String() == locale
+	})
+}
+
+// MatchLocale returns the supported locale closest to the requested ones, the default locale when nothing is close
+// enough
+func MatchLocale(requested ...language.Tag) string {
+	tags, matcher := locales()
+	_, index, _ := matcher.Match(requested...)
+	return tags[index].String()
+}
+
+// Localization picks the locale of the response among the supported ones, from the ?locale= parameter or else the
+// Accept-Language header, and sends it back in the Content-Language header
+func Localization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requested []language.Tag
+		if raw := c.Query(LocaleParam); raw != "" {
+			tag, err := language.Parse(raw)
+			if err != nil {
+				InvalidRequest(c, "the request has invalid fields", models.ValidationErrors{
+					{Field: LocaleParam, Message: "must be a language tag such as de or en-GB"},
+				})
+				c.Abort()
+				return
+			}
+			requested = append(requested, tag)
+		} else {
+			// a malformed header is ignored like a missing one
+			requested, _, _ = language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		}
+
+		locale := MatchLocale(requested...)
+		c.Set(localeKey, locale)
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// Locale returns the locale the Localization middleware picked for the request, the default one outside of it
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(localeKey); locale != "" {
+		return locale
+	}
+	return DefaultLocale()
+}
+
+// WithLocale returns a copy of ctx with the locale, for the handlers such as GraphQL that only see the request context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext returns the locale WithLocale stored, the default one when there is none
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeContextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale()
+}
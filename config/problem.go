@@ -0,0 +1,110 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+const (
+	// CorrelationIDHeader carries the id that ties a response to the server logs, it is kept when the client sends one
+	CorrelationIDHeader = "X-Correlation-ID"
+	correlationIDKey    = "correlationID"
+
+	// ProblemContentType is the media type of the RFC 7807 problem details documents
+	ProblemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:mytheresa:problem:"
+)
+
+// validCorrelationID keeps the ids sent by clients short and safe to log
+var validCorrelationID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// kindStatus is the status code of every error kind
+var kindStatus = map[models.ErrorKind]int{
+	models.ErrorKindValidation:    http.StatusBadRequest,
+	models.ErrorKindNotFound:      http.StatusNotFound,
+	models.ErrorKindConflict:      http.StatusConflict,
+	models.ErrorKindTooLarge:      http.StatusRequestEntityTooLarge,
+	models.ErrorKindNotAcceptable: http.StatusNotAcceptable,
+	models.ErrorKindInternal:      http.StatusInternalServerError,
+	models.ErrorKindUnavailable:   http.StatusServiceUnavailable,
+}
+
+// ProblemDetails is the RFC 7807 body of every error response, code and correlationId are extension members
+type ProblemDetails struct {
+	Type          string                  `json:"type"`
+	Title         string                  `json:"title"`
+	Status        int                     `json:"status"`
+	Detail        string                  `json:"detail"`
+	Instance      string                  `json:"instance"`
+	Code          string                  `json:"code"`
+	CorrelationID string                  `json:"correlationId"`
+	Errors        models.ValidationErrors `json:"errors,omitempty"`
+}
+
+// CorrelationID gives every request an id, it is sent back in the X-Correlation-ID header and in the error responses
+func CorrelationID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id, ok := c.Request.Context().Value(reroutedKey{}).(string); ok {
+			// the v2 twin of a negotiated request keeps the id the first pass sent back
+			c.Set(correlationIDKey, id)
+			c.Next()
+			return
+		}
+
+		id := c.GetHeader(CorrelationIDHeader)
+		if !validCorrelationID.MatchString(id) {
+			id = newCorrelationID()
+		}
+		c.Set(correlationIDKey, id)
+		c.Header(CorrelationIDHeader, id)
+		c.Next()
+	}
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GetCorrelationID returns the id the CorrelationID middleware gave the request
+func GetCorrelationID(c *gin.Context) string {
+	return c.GetString(correlationIDKey)
+}
+
+// Problem serializes the error as application/problem+json. Only the code, the detail and the invalid fields reach
+// the client, the wrapped error of the internal errors is logged with the correlation id instead
+func Problem(c *gin.Context, err *models.Error) {
+	status, ok := kindStatus[err.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	correlationID := GetCorrelationID(c)
+	if status >= http.StatusInternalServerError {
+		logrus.WithError(err).WithFields(logrus.Fields{"correlationId": correlationID, "code": err.Code}).Error("request failed")
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, ProblemDetails{
+		Type:          problemTypePrefix + err.Code,
+		Title:         http.StatusText(status),
+		Status:        status,
+		Detail:        err.Detail,
+		Instance:      c.Request.URL.Path,
+		Code:          err.Code,
+		CorrelationID: correlationID,
+		Errors:        err.Fields,
+	})
+}
+
+// InvalidRequest is a shortcut to reject a request with the list of its invalid fields
+func InvalidRequest(c *gin.Context, detail string, fields models.ValidationErrors) {
+	Problem(c, &models.Error{Kind: models.ErrorKindValidation, Code: "invalid_request", Detail: detail, Fields: fields})
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+const (
+	MIMECSV     = "text/csv"
+	MIMEMsgPack = "application/msgpack"
+
+	formatKey = "responseFormat"
+)
+
+// RendererFunc builds the renderer of a successful response in another format than JSON, data is what the handler
+// passed to JSON and body is the same data in the envelope of the api version. It reports false when the format
+// can't represent the data, the response is then sent as JSON
+type RendererFunc func(data, body any) (render.Render, bool)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]RendererFunc{
+		MIMECSV:     csvRenderer,
+		MIMEMsgPack: msgpackRenderer,
+	}
+)
+
+// RegisterRenderer lets the responses of JSON be sent as the media type when the Accept header prefers it,
+// registering a media type again replaces its renderer
+func RegisterRenderer(mediaType string, renderer RendererFunc) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[mediaType] = renderer
+}
+
+// ResponseFormat returns the media type the Accept header picked among JSON and the registered renderers,
+// JSON when the header doesn't name any of them
+func ResponseFormat(c *gin.Context) string {
+	if format := c.GetString(formatKey); format != "" {
+		return format
+	}
+
+	renderersMu.RLock()
+	offered := append([]string{gin.MIMEJSON}, slices.Sorted(maps.Keys(renderers))...)
+	renderersMu.RUnlock()
+
+	format := negotiate(c.GetHeader("Accept"), offered, mediaRanges)
+	if format == "" {
+		format = gin.MIMEJSON
+	}
+	c.Set(formatKey, format)
+	return format
+}
+
+// NegotiateEncoding returns the content coding the Accept-Encoding header prefers among the offered ones, e.g. gzip or
+// identity. It is empty when the header is missing or accepts none of them
+func NegotiateEncoding(acceptEncoding string, offered ...string) string {
+	return negotiate(acceptEncoding, offered, func(coding string) []string {
+		return []string{coding, "*"}
+	})
+}
+
+// mediaRanges are the ranges of an Accept header that match the media type, from the most to the least specific
+func mediaRanges(mediaType string) []string {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	return []string{mediaType, typ + "/*", "*/*"}
+}
+
+// negotiate returns the offer an Accept style header prefers, matching lists the values of the header that match an
+// offer from the most to the least specific. Every offer gets the q-value of its most specific match, the highest
+// q-value wins and a tie goes to the value listed first in the header and then to the first offer. A value with q=0
+// is never picked, it is empty when the header accepts none of the offers
+func negotiate(header string, offered []string, matching func(offer string) []string) string {
+	if strings.TrimSpace(header) == "" {
+		return ""
+	}
+
+	type acceptRange struct {
+		value string
+		q     float64
+	}
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		value, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{value: value, q: q})
+	}
+
+	best, bestQ, bestPosition := "", 0.0, 0
+	for _, offer := range offered {
+		matches := matching(offer)
+		position, specificity := -1, -1
+		for i, r := range ranges {
+			m := slices.Index(matches, r.value)
+			if m < 0 {
+				continue
+			}
+			if s := len(matches) - m; s > specificity {
+				position, specificity = i, s
+			}
+		}
+		if position < 0 {
+			continue
+		}
+		if q := ranges[position].q; q > bestQ || (q == bestQ && position < bestPosition) {
+			best, bestQ, bestPosition = offer, q, position
+		}
+	}
+	return best
+}
+
+// renderAs sends the response with the renderer of the negotiated format, it reports false when the response
+// still has to be sent as JSON
+func renderAs(c *gin.Context, status int, data, body any) bool {
+	format := ResponseFormat(c)
+	if format == gin.MIMEJSON {
+		return false
+	}
+
+	renderersMu.RLock()
+	renderer := renderers[format]
+	renderersMu.RUnlock()
+
+	r, ok := renderer(data, body)
+	if !ok {
+		return false
+	}
+	c.Render(status, r)
+	return true
+}
+
+// csvRenderer writes the records of the tabular responses such as the product listings, the envelope and the
+// page meta are left out
+func csvRenderer(data, _ any) (render.Render, bool) {
+	table, ok := data.(models.Tabular)
+	if !ok {
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(table.Columns())
+	_ = w.WriteAll(table.Records())
+	if w.Error() != nil {
+		return nil, false
+	}
+	return render.Data{ContentType: MIMECSV + "; charset=utf-8", Data: buf.Bytes()}, true
+}
+
+// msgpackRenderer writes the body with the keys and values of its JSON, so both formats hold the same document
+func msgpackRenderer(_, body any) (render.Render, bool) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, false
+	}
+	return render.MsgPack{Data: msgpackValue(document)}, true
+}
+
+// msgpackValue turns the JSON numbers into msgpack integers, or floats when they have a fraction
+func msgpackValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = msgpackValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = msgpackValue(item)
+		}
+	}
+	return value
+}
@@ -2,29 +2,42 @@ package config
 
 import (
 	"net/http"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/models"
 )
 
-// JSON serializes the api response properly to json
+// JSON serializes the api response properly to json in the envelope of the api version, or in the format of a
+// registered renderer when the Accept header asks for it. The translated texts are in the locale of the request and
+// the errors are rendered with Problem
 func JSON(c *gin.Context, message string, status int, data any) {
-	responseData := gin.H{}
+	if l, ok := data.(models.Localizable); ok {
+		l.Localize(Locale(c))
+	}
 
-	switch data.(type) {
-	case error:
-		responseData = gin.H{
-			"message":    message,
-			"errors":     data.(error).Error(),
-			"status":     http.StatusText(status),
-			"statusCode": status,
+	if APIVersion(c) == V2 {
+		// the v2 listings are always arrays, never null
+		if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+			data = []any{}
 		}
-	default:
-		responseData = gin.H{
-			"message":    message,
-			"data":       data,
-			"status":     http.StatusText(status),
-			"statusCode": status,
+		doc := newDocument(c, data)
+		if renderAs(c, status, data, doc) {
+			return
 		}
+		// the links hold query strings, PureJSON keeps their & unescaped
+		c.PureJSON(status, doc)
+		return
+	}
+
+	body := gin.H{
+		"message":    message,
+		"data":       data,
+		"status":     http.StatusText(status),
+		"statusCode": status,
+	}
+	if renderAs(c, status, data, body) {
+		return
 	}
-	c.JSON(status, responseData)
+	c.JSON(status, body)
 }
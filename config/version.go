@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"mime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+const (
+	V1 = "v1"
+	V2 = "v2"
+
+	// APIVersionHeader tells the client which version rendered the response
+	APIVersionHeader = "API-Version"
+	versionKey       = "apiVersion"
+
+	// the vendor media types that pick a version with the Accept header, e.g. Accept: application/vnd.mytheresa.v2+json
+	mediaTypePrefix = "application/vnd.mytheresa."
+	mediaTypeSuffix = "+json"
+)
+
+// basePaths is the prefix of the routes of every version
+var basePaths = map[string]string{
+	V1: "/api",
+	V2: "/api/v2",
+}
+
+// Version marks the responses of the group with the api version. A request whose Accept header only asks for
+// another version is rejected with a 406
+func Version(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if accepted := acceptedVersion(c.GetHeader("Accept")); accepted != "" && accepted != version {
+			Problem(c, models.NewError(models.ErrorKindNotAcceptable, "version_not_acceptable",
+				"this route only serves the "+version+" representation"))
+			c.Abort()
+			return
+		}
+		c.Set(versionKey, version)
+		c.Header(APIVersionHeader, version)
+		c.Writer.Header().Add("Vary", "Accept")
+		c.Next()
+	}
+}
+
+// NegotiateVersion serves a request to a v1 route through its v2 twin when the Accept header asks for v2,
+// the routes without a v2 twin keep serving v1. It runs before the routes are matched again so the twin route
+// gets its own middlewares
+func NegotiateVersion(engine *gin.Engine) gin.HandlerFunc {
+	v2Routes := sync.OnceValue(func() map[string]bool {
+		routes := map[string]bool{}
+		for _, route := range engine.Routes() {
+			if strings.HasPrefix(route.Path, basePaths[V2]+"/") {
+				routes[route.Method+" "+route.Path] = true
+			}
+		}
+		return routes
+	})
+
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if acceptedVersion(c.GetHeader("Accept")) != V2 || strings.HasPrefix(path, basePaths[V2]+"/") ||
+			!v2Routes()[c.Request.Method+" "+basePaths[V2]+strings.TrimPrefix(path, basePaths[V1])] {
+			c.Next()
+			return
+		}
+
+		// the global middlewares run again for the twin, the mark tells them the request was already logged and
+		// identified
+		first := c.Request
+		twin := *first.URL
+		twin.Path = basePaths[V2] + strings.TrimPrefix(twin.Path, basePaths[V1])
+		twin.RawPath = ""
+		c.Request = first.WithContext(context.WithValue(first.Context(), reroutedKey{}, GetCorrelationID(c)))
+		c.Request.URL = &twin
+		engine.HandleContext(c)
+		c.Request = first
+		c.Abort()
+	}
+}
+
+// reroutedKey marks the requests NegotiateVersion serves again through their v2 twin, it holds the correlation id of
+// the first pass
+type reroutedKey struct{}
+
+// Rerouted reports whether the request is the second pass NegotiateVersion makes through the v2 twin of a route,
+// e.g. to skip the access log the first pass already writes
+func Rerouted(c *gin.Context) bool {
+	_, ok := c.Request.Context().Value(reroutedKey{}).(string)
+	return ok
+}
+
+// acceptedVersion returns the version of the vendor media type in the Accept header, it is empty when the header
+// doesn't name one so plain application/json keeps the version of the route
+func acceptedVersion(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || !strings.HasPrefix(mediaType, mediaTypePrefix) || !strings.HasSuffix(mediaType, mediaTypeSuffix) {
+			continue
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(mediaType, mediaTypePrefix), mediaTypeSuffix)
+		if _, ok := basePaths[version]; ok {
+			return version
+		}
+	}
+	return ""
+}
+
+// APIVersion returns the version that renders the response, v1 unless the route belongs to another version
+func APIVersion(c *gin.Context) string {
+	if version := c.GetString(versionKey); version != "" {
+		return version
+	}
+	return V1
+}
+
+// BasePath returns the prefix of the routes of the version that renders the response, e.g. to build a Location header
+func BasePath(c *gin.Context) string {
+	return basePaths[APIVersion(c)]
+}
+
+// Links are the hypermedia links of a v2 document, the pagination links are only set for listings
+type Links struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// Document is the v2 envelope, the status is only sent in the status line
+type Document struct {
+	Data  any          `json:"data"`
+	Meta  *models.Meta `json:"meta,omitempty"`
+	Links Links        `json:"links"`
+}
+
+// newDocument wraps the data in the v2 envelope, a page of a listing is split into its items and meta
+func newDocument(c *gin.Context, data any) Document {
+	doc := Document{Data: data, Links: Links{Self: c.Request.URL.RequestURI()}}
+
+	page, ok := data.(models.Paginated)
+	if !ok {
+		return doc
+	}
+	meta := page.PageMeta()
+	doc.Data = page.PageItems()
+	doc.Meta = &meta
+	if meta.TotalPages == 0 {
+		return doc
+	}
+	doc.Links.First = pageLink(c, 1)
+	doc.Links.Last = pageLink(c, meta.TotalPages)
+	if meta.Page > 1 {
+		doc.Links.Prev = pageLink(c, min(meta.Page-1, meta.TotalPages))
+	}
+	if meta.Page < meta.TotalPages {
+		doc.Links.Next = pageLink(c, meta.Page+1)
+	}
+	return doc
+}
+
+// pageLink is the url of the request with another page
+func pageLink(c *gin.Context, page int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
@@ -3,6 +3,7 @@
 package ent
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -21,6 +22,8 @@ type Category struct {
 	Name string `json:"name,omitempty"`
 	// Description holds the value of the "description" field.
 	Description *string `json:"description,omitempty"`
+	// DescriptionTranslations holds the value of the "description_translations" field.
+	DescriptionTranslations map[string]string `json:"description_translations,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -38,6 +41,10 @@ type CategoryEdges struct {
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
 	loadedTypes [1]bool
+	// totalCount holds the count of the edges above.
+	totalCount [1]map[string]int
+
+	namedProducts map[string][]*Product
 }
 
 // ProductsOrErr returns the Products value or an error if the edge
@@ -54,6 +61,8 @@ func (*Category) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case category.FieldDescriptionTranslations:
+			values[i] = new([]byte)
 		case category.FieldID:
 			values[i] = new(sql.NullInt64)
 		case category.FieldName, category.FieldDescription:
@@ -94,6 +103,14 @@ func (c *Category) assignValues(columns []string, values []any) error {
 				c.Description = new(string)
 				*c.Description = value.String
 			}
+		case category.FieldDescriptionTranslations:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field description_translations", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &c.DescriptionTranslations); err != nil {
+					return fmt.Errorf("unmarshal field description_translations: %w", err)
+				}
+			}
 		case category.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -155,6 +172,9 @@ func (c *Category) String() string {
 		builder.WriteString(*v)
 	}
 	builder.WriteString(", ")
+	builder.WriteString("description_translations=")
+	builder.WriteString(fmt.Sprintf("%v", c.DescriptionTranslations))
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(c.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
@@ -164,5 +184,29 @@ func (c *Category) String() string {
 	return builder.String()
 }
 
+// NamedProducts returns the Products named value or an error if the edge was not
+// loaded in eager-loading with this name.
+func (c *Category) NamedProducts(name string) ([]*Product, error) {
+	if c.Edges.namedProducts == nil {
+		return nil, &NotLoadedError{edge: name}
+	}
+	nodes, ok := c.Edges.namedProducts[name]
+	if !ok {
+		return nil, &NotLoadedError{edge: name}
+	}
+	return nodes, nil
+}
+
+func (c *Category) appendNamedProducts(name string, edges ...*Product) {
+	if c.Edges.namedProducts == nil {
+		c.Edges.namedProducts = make(map[string][]*Product)
+	}
+	if len(edges) == 0 {
+		c.Edges.namedProducts[name] = []*Product{}
+	} else {
+		c.Edges.namedProducts[name] = append(c.Edges.namedProducts[name], edges...)
+	}
+}
+
 // Categories is a parsable slice of Category.
 type Categories []*Category
@@ -205,6 +205,16 @@ func DescriptionContainsFold(v string) predicate.Category {
 	return predicate.Category(sql.FieldContainsFold(FieldDescription, v))
 }
 
+// DescriptionTranslationsIsNil applies the IsNil predicate on the "description_translations" field.
+func DescriptionTranslationsIsNil() predicate.Category {
+	return predicate.Category(sql.FieldIsNull(FieldDescriptionTranslations))
+}
+
+// DescriptionTranslationsNotNil applies the NotNil predicate on the "description_translations" field.
+func DescriptionTranslationsNotNil() predicate.Category {
+	return predicate.Category(sql.FieldNotNull(FieldDescriptionTranslations))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Category {
 	return predicate.Category(sql.FieldEQ(FieldCreatedAt, v))
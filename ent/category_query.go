@@ -19,11 +19,14 @@ import (
 // CategoryQuery is the builder for querying Category entities.
 type CategoryQuery struct {
 	config
-	ctx          *QueryContext
-	order        []category.OrderOption
-	inters       []Interceptor
-	predicates   []predicate.Category
-	withProducts *ProductQuery
+	ctx               *QueryContext
+	order             []category.OrderOption
+	inters            []Interceptor
+	predicates        []predicate.Category
+	withProducts      *ProductQuery
+	loadTotal         []func(context.Context, []*Category) error
+	modifiers         []func(*sql.Selector)
+	withNamedProducts map[string]*ProductQuery
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -383,6 +386,9 @@ func (cq *CategoryQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Cat
 		node.Edges.loadedTypes = loadedTypes
 		return node.assignValues(columns, values)
 	}
+	if len(cq.modifiers) > 0 {
+		_spec.Modifiers = cq.modifiers
+	}
 	for i := range hooks {
 		hooks[i](ctx, _spec)
 	}
@@ -399,6 +405,18 @@ func (cq *CategoryQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Cat
 			return nil, err
 		}
 	}
+	for name, query := range cq.withNamedProducts {
+		if err := cq.loadProducts(ctx, query, nodes,
+			func(n *Category) { n.appendNamedProducts(name) },
+			func(n *Category, e *Product) { n.appendNamedProducts(name, e) }); err != nil {
+			return nil, err
+		}
+	}
+	for i := range cq.loadTotal {
+		if err := cq.loadTotal[i](ctx, nodes); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -436,6 +454,9 @@ func (cq *CategoryQuery) loadProducts(ctx context.Context, query *ProductQuery,
 
 func (cq *CategoryQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := cq.querySpec()
+	if len(cq.modifiers) > 0 {
+		_spec.Modifiers = cq.modifiers
+	}
 	_spec.Node.Columns = cq.ctx.Fields
 	if len(cq.ctx.Fields) > 0 {
 		_spec.Unique = cq.ctx.Unique != nil && *cq.ctx.Unique
@@ -498,6 +519,9 @@ func (cq *CategoryQuery) sqlQuery(ctx context.Context) *sql.Selector {
 	if cq.ctx.Unique != nil && *cq.ctx.Unique {
 		selector.Distinct()
 	}
+	for _, m := range cq.modifiers {
+		m(selector)
+	}
 	for _, p := range cq.predicates {
 		p(selector)
 	}
@@ -515,6 +539,26 @@ func (cq *CategoryQuery) sqlQuery(ctx context.Context) *sql.Selector {
 	return selector
 }
 
+// Modify adds a query modifier for attaching custom logic to queries.
+func (cq *CategoryQuery) Modify(modifiers ...func(s *sql.Selector)) *CategorySelect {
+	cq.modifiers = append(cq.modifiers, modifiers...)
+	return cq.Select()
+}
+
+// WithNamedProducts tells the query-builder to eager-load the nodes that are connected to the "products"
+// edge with the given name. The optional arguments are used to configure the query builder of the edge.
+func (cq *CategoryQuery) WithNamedProducts(name string, opts ...func(*ProductQuery)) *CategoryQuery {
+	query := (&ProductClient{config: cq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	if cq.withNamedProducts == nil {
+		cq.withNamedProducts = make(map[string]*ProductQuery)
+	}
+	cq.withNamedProducts[name] = query
+	return cq
+}
+
 // CategoryGroupBy is the group-by builder for Category entities.
 type CategoryGroupBy struct {
 	selector
@@ -604,3 +648,9 @@ func (cs *CategorySelect) sqlScan(ctx context.Context, root *CategoryQuery, v an
 	defer rows.Close()
 	return sql.ScanSlice(rows, v)
 }
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (cs *CategorySelect) Modify(modifiers ...func(s *sql.Selector)) *CategorySelect {
+	cs.modifiers = append(cs.modifiers, modifiers...)
+	return cs
+}
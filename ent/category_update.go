@@ -19,8 +19,9 @@ import (
 // CategoryUpdate is the builder for updating Category entities.
 type CategoryUpdate struct {
 	config
-	hooks    []Hook
-	mutation *CategoryMutation
+	hooks     []Hook
+	mutation  *CategoryMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // Where appends a list predicates to the CategoryUpdate builder.
@@ -57,6 +58,18 @@ func (cu *CategoryUpdate) SetNillableDescription(s *string) *CategoryUpdate {
 	return cu
 }
 
+// SetDescriptionTranslations sets the "description_translations" field.
+func (cu *CategoryUpdate) SetDescriptionTranslations(m map[string]string) *CategoryUpdate {
+	cu.mutation.SetDescriptionTranslations(m)
+	return cu
+}
+
+// ClearDescriptionTranslations clears the value of the "description_translations" field.
+func (cu *CategoryUpdate) ClearDescriptionTranslations() *CategoryUpdate {
+	cu.mutation.ClearDescriptionTranslations()
+	return cu
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (cu *CategoryUpdate) SetCreatedAt(t time.Time) *CategoryUpdate {
 	cu.mutation.SetCreatedAt(t)
@@ -77,14 +90,6 @@ func (cu *CategoryUpdate) SetUpdatedAt(t time.Time) *CategoryUpdate {
 	return cu
 }
 
-// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
-func (cu *CategoryUpdate) SetNillableUpdatedAt(t *time.Time) *CategoryUpdate {
-	if t != nil {
-		cu.SetUpdatedAt(*t)
-	}
-	return cu
-}
-
 // AddProductIDs adds the "products" edge to the Product entity by IDs.
 func (cu *CategoryUpdate) AddProductIDs(ids ...int) *CategoryUpdate {
 	cu.mutation.AddProductIDs(ids...)
@@ -128,6 +133,7 @@ func (cu *CategoryUpdate) RemoveProducts(p ...*Product) *CategoryUpdate {
 
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (cu *CategoryUpdate) Save(ctx context.Context) (int, error) {
+	cu.defaults()
 	return withHooks(ctx, cu.sqlSave, cu.mutation, cu.hooks)
 }
 
@@ -153,6 +159,14 @@ func (cu *CategoryUpdate) ExecX(ctx context.Context) {
 	}
 }
 
+// defaults sets the default values of the builder before save.
+func (cu *CategoryUpdate) defaults() {
+	if _, ok := cu.mutation.UpdatedAt(); !ok {
+		v := category.UpdateDefaultUpdatedAt()
+		cu.mutation.SetUpdatedAt(v)
+	}
+}
+
 // check runs all checks and user-defined validators on the builder.
 func (cu *CategoryUpdate) check() error {
 	if v, ok := cu.mutation.Name(); ok {
@@ -163,6 +177,12 @@ func (cu *CategoryUpdate) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (cu *CategoryUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *CategoryUpdate {
+	cu.modifiers = append(cu.modifiers, modifiers...)
+	return cu
+}
+
 func (cu *CategoryUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if err := cu.check(); err != nil {
 		return n, err
@@ -181,6 +201,12 @@ func (cu *CategoryUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := cu.mutation.Description(); ok {
 		_spec.SetField(category.FieldDescription, field.TypeString, value)
 	}
+	if value, ok := cu.mutation.DescriptionTranslations(); ok {
+		_spec.SetField(category.FieldDescriptionTranslations, field.TypeJSON, value)
+	}
+	if cu.mutation.DescriptionTranslationsCleared() {
+		_spec.ClearField(category.FieldDescriptionTranslations, field.TypeJSON)
+	}
 	if value, ok := cu.mutation.CreatedAt(); ok {
 		_spec.SetField(category.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -232,6 +258,7 @@ func (cu *CategoryUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(cu.modifiers...)
 	if n, err = sqlgraph.UpdateNodes(ctx, cu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{category.Label}
@@ -247,9 +274,10 @@ func (cu *CategoryUpdate) sqlSave(ctx context.Context) (n int, err error) {
 // CategoryUpdateOne is the builder for updating a single Category entity.
 type CategoryUpdateOne struct {
 	config
-	fields   []string
-	hooks    []Hook
-	mutation *CategoryMutation
+	fields    []string
+	hooks     []Hook
+	mutation  *CategoryMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // SetName sets the "name" field.
@@ -280,6 +308,18 @@ func (cuo *CategoryUpdateOne) SetNillableDescription(s *string) *CategoryUpdateO
 	return cuo
 }
 
+// SetDescriptionTranslations sets the "description_translations" field.
+func (cuo *CategoryUpdateOne) SetDescriptionTranslations(m map[string]string) *CategoryUpdateOne {
+	cuo.mutation.SetDescriptionTranslations(m)
+	return cuo
+}
+
+// ClearDescriptionTranslations clears the value of the "description_translations" field.
+func (cuo *CategoryUpdateOne) ClearDescriptionTranslations() *CategoryUpdateOne {
+	cuo.mutation.ClearDescriptionTranslations()
+	return cuo
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (cuo *CategoryUpdateOne) SetCreatedAt(t time.Time) *CategoryUpdateOne {
 	cuo.mutation.SetCreatedAt(t)
@@ -300,14 +340,6 @@ func (cuo *CategoryUpdateOne) SetUpdatedAt(t time.Time) *CategoryUpdateOne {
 	return cuo
 }
 
-// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
-func (cuo *CategoryUpdateOne) SetNillableUpdatedAt(t *time.Time) *CategoryUpdateOne {
-	if t != nil {
-		cuo.SetUpdatedAt(*t)
-	}
-	return cuo
-}
-
 // AddProductIDs adds the "products" edge to the Product entity by IDs.
 func (cuo *CategoryUpdateOne) AddProductIDs(ids ...int) *CategoryUpdateOne {
 	cuo.mutation.AddProductIDs(ids...)
@@ -364,6 +396,7 @@ func (cuo *CategoryUpdateOne) Select(field string, fields ...string) *CategoryUp
 
 // Save executes the query and returns the updated Category entity.
 func (cuo *CategoryUpdateOne) Save(ctx context.Context) (*Category, error) {
+	cuo.defaults()
 	return withHooks(ctx, cuo.sqlSave, cuo.mutation, cuo.hooks)
 }
 
@@ -389,6 +422,14 @@ func (cuo *CategoryUpdateOne) ExecX(ctx context.Context) {
 	}
 }
 
+// defaults sets the default values of the builder before save.
+func (cuo *CategoryUpdateOne) defaults() {
+	if _, ok := cuo.mutation.UpdatedAt(); !ok {
+		v := category.UpdateDefaultUpdatedAt()
+		cuo.mutation.SetUpdatedAt(v)
+	}
+}
+
 // check runs all checks and user-defined validators on the builder.
 func (cuo *CategoryUpdateOne) check() error {
 	if v, ok := cuo.mutation.Name(); ok {
@@ -399,6 +440,12 @@ func (cuo *CategoryUpdateOne) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (cuo *CategoryUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *CategoryUpdateOne {
+	cuo.modifiers = append(cuo.modifiers, modifiers...)
+	return cuo
+}
+
 func (cuo *CategoryUpdateOne) sqlSave(ctx context.Context) (_node *Category, err error) {
 	if err := cuo.check(); err != nil {
 		return _node, err
@@ -434,6 +481,12 @@ func (cuo *CategoryUpdateOne) sqlSave(ctx context.Context) (_node *Category, err
 	if value, ok := cuo.mutation.Description(); ok {
 		_spec.SetField(category.FieldDescription, field.TypeString, value)
 	}
+	if value, ok := cuo.mutation.DescriptionTranslations(); ok {
+		_spec.SetField(category.FieldDescriptionTranslations, field.TypeJSON, value)
+	}
+	if cuo.mutation.DescriptionTranslationsCleared() {
+		_spec.ClearField(category.FieldDescriptionTranslations, field.TypeJSON)
+	}
 	if value, ok := cuo.mutation.CreatedAt(); ok {
 		_spec.SetField(category.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -485,6 +538,7 @@ func (cuo *CategoryUpdateOne) sqlSave(ctx context.Context) (_node *Category, err
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(cuo.modifiers...)
 	_node = &Category{config: cuo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
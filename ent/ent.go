@@ -13,7 +13,10 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/importjob"
 	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/ent/webhook"
+	"github.com/tonymj76/mytheresa-test/ent/webhookdelivery"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -74,8 +77,11 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			category.Table: category.ValidColumn,
-			product.Table:  product.ValidColumn,
+			category.Table:        category.ValidColumn,
+			importjob.Table:       importjob.ValidColumn,
+			product.Table:         product.ValidColumn,
+			webhook.Table:         webhook.ValidColumn,
+			webhookdelivery.Table: webhookdelivery.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
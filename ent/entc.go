@@ -0,0 +1,31 @@
+//go:build ignore
+
+package main
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent/entc"
+	"entgo.io/ent/entc/gen"
+	"log"
+)
+
+// main runs the ent codegen with the entgql extension, which also writes the GraphQL schema of the ent types
+// to graph/ent.graphql for gqlgen
+func main() {
+	ex, err := entgql.NewExtension(
+		entgql.WithConfigPath("../graph/gqlgen.yml"),
+		entgql.WithSchemaGenerator(),
+		entgql.WithSchemaPath("../graph/ent.graphql"),
+		entgql.WithWhereInputs(false),
+	)
+	if err != nil {
+		log.Fatalf("creating entgql extension: %v", err)
+	}
+
+	err = entc.Generate("./schema", &gen.Config{
+		Features: []gen.Feature{gen.FeatureModifier, gen.FeatureExecQuery, gen.FeatureUpsert},
+	}, entc.Extensions(ex))
+	if err != nil {
+		log.Fatalf("running ent codegen: %v", err)
+	}
+}
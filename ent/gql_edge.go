@@ -0,0 +1,37 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func (c *Category) Products(
+	ctx context.Context, after *Cursor, first *int, before *Cursor, last *int, orderBy *ProductOrder,
+) (*ProductConnection, error) {
+	opts := []ProductPaginateOption{
+		WithProductOrder(orderBy),
+	}
+	alias := graphql.GetFieldContext(ctx).Field.Alias
+	totalCount, hasTotalCount := c.Edges.totalCount[0][alias]
+	if nodes, err := c.NamedProducts(alias); err == nil || hasTotalCount {
+		pager, err := newProductPager(opts, last != nil)
+		if err != nil {
+			return nil, err
+		}
+		conn := &ProductConnection{Edges: []*ProductEdge{}, TotalCount: totalCount}
+		conn.build(nodes, pager, after, first, before, last)
+		return conn, nil
+	}
+	return c.QueryProducts().Paginate(ctx, after, first, before, last, opts...)
+}
+
+func (pr *Product) Category(ctx context.Context) (*Category, error) {
+	result, err := pr.Edges.CategoryOrErr()
+	if IsNotLoaded(err) {
+		result, err = pr.QueryCategory().Only(ctx)
+	}
+	return result, MaskNotFound(err)
+}
@@ -0,0 +1,236 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/tonymj76/mytheresa-test/ent/importjob"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+// ImportJob is the model entity for the ImportJob schema.
+type ImportJob struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Status holds the value of the "status" field.
+	Status importjob.Status `json:"status,omitempty"`
+	// Mode holds the value of the "mode" field.
+	Mode importjob.Mode `json:"mode,omitempty"`
+	// Format holds the value of the "format" field.
+	Format string `json:"format,omitempty"`
+	// TotalRows holds the value of the "total_rows" field.
+	TotalRows int `json:"total_rows,omitempty"`
+	// ProcessedRows holds the value of the "processed_rows" field.
+	ProcessedRows int `json:"processed_rows,omitempty"`
+	// CreatedRows holds the value of the "created_rows" field.
+	CreatedRows int `json:"created_rows,omitempty"`
+	// UpdatedRows holds the value of the "updated_rows" field.
+	UpdatedRows int `json:"updated_rows,omitempty"`
+	// FailedRows holds the value of the "failed_rows" field.
+	FailedRows int `json:"failed_rows,omitempty"`
+	// Errors holds the value of the "errors" field.
+	Errors []models.RowError `json:"errors,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// FinishedAt holds the value of the "finished_at" field.
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*ImportJob) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case importjob.FieldErrors:
+			values[i] = new([]byte)
+		case importjob.FieldID, importjob.FieldTotalRows, importjob.FieldProcessedRows, importjob.FieldCreatedRows, importjob.FieldUpdatedRows, importjob.FieldFailedRows:
+			values[i] = new(sql.NullInt64)
+		case importjob.FieldStatus, importjob.FieldMode, importjob.FieldFormat:
+			values[i] = new(sql.NullString)
+		case importjob.FieldCreatedAt, importjob.FieldUpdatedAt, importjob.FieldFinishedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the ImportJob fields.
+func (ij *ImportJob) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case importjob.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ij.ID = int(value.Int64)
+		case importjob.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				ij.Status = importjob.Status(value.String)
+			}
+		case importjob.FieldMode:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field mode", values[i])
+			} else if value.Valid {
+				ij.Mode = importjob.Mode(value.String)
+			}
+		case importjob.FieldFormat:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field format", values[i])
+			} else if value.Valid {
+				ij.Format = value.String
+			}
+		case importjob.FieldTotalRows:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field total_rows", values[i])
+			} else if value.Valid {
+				ij.TotalRows = int(value.Int64)
+			}
+		case importjob.FieldProcessedRows:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field processed_rows", values[i])
+			} else if value.Valid {
+				ij.ProcessedRows = int(value.Int64)
+			}
+		case importjob.FieldCreatedRows:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field created_rows", values[i])
+			} else if value.Valid {
+				ij.CreatedRows = int(value.Int64)
+			}
+		case importjob.FieldUpdatedRows:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_rows", values[i])
+			} else if value.Valid {
+				ij.UpdatedRows = int(value.Int64)
+			}
+		case importjob.FieldFailedRows:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field failed_rows", values[i])
+			} else if value.Valid {
+				ij.FailedRows = int(value.Int64)
+			}
+		case importjob.FieldErrors:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field errors", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &ij.Errors); err != nil {
+					return fmt.Errorf("unmarshal field errors: %w", err)
+				}
+			}
+		case importjob.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ij.CreatedAt = value.Time
+			}
+		case importjob.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				ij.UpdatedAt = value.Time
+			}
+		case importjob.FieldFinishedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field finished_at", values[i])
+			} else if value.Valid {
+				ij.FinishedAt = new(time.Time)
+				*ij.FinishedAt = value.Time
+			}
+		default:
+			ij.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the ImportJob.
+// This includes values selected through modifiers, order, etc.
+func (ij *ImportJob) Value(name string) (ent.Value, error) {
+	return ij.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this ImportJob.
+// Note that you need to call ImportJob.Unwrap() before calling this method if this ImportJob
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ij *ImportJob) Update() *ImportJobUpdateOne {
+	return NewImportJobClient(ij.config).UpdateOne(ij)
+}
+
+// Unwrap unwraps the ImportJob entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ij *ImportJob) Unwrap() *ImportJob {
+	_tx, ok := ij.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: ImportJob is not a transactional entity")
+	}
+	ij.config.driver = _tx.drv
+	return ij
+}
+
+// String implements the fmt.Stringer.
+func (ij *ImportJob) String() string {
+	var builder strings.Builder
+	builder.WriteString("ImportJob(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ij.ID))
+	builder.WriteString("status=")
+	builder.WriteString(fmt.Sprintf("%v", ij.Status))
+	builder.WriteString(", ")
+	builder.WriteString("mode=")
+	builder.WriteString(fmt.Sprintf("%v", ij.Mode))
+	builder.WriteString(", ")
+	builder.WriteString("format=")
+	builder.WriteString(ij.Format)
+	builder.WriteString(", ")
+	builder.WriteString("total_rows=")
+	builder.WriteString(fmt.Sprintf("%v", ij.TotalRows))
+	builder.WriteString(", ")
+	builder.WriteString("processed_rows=")
+	builder.WriteString(fmt.Sprintf("%v", ij.ProcessedRows))
+	builder.WriteString(", ")
+	builder.WriteString("created_rows=")
+	builder.WriteString(fmt.Sprintf("%v", ij.CreatedRows))
+	builder.WriteString(", ")
+	builder.WriteString("updated_rows=")
+	builder.WriteString(fmt.Sprintf("%v", ij.UpdatedRows))
+	builder.WriteString(", ")
+	builder.WriteString("failed_rows=")
+	builder.WriteString(fmt.Sprintf("%v", ij.FailedRows))
+	builder.WriteString(", ")
+	builder.WriteString("errors=")
+	builder.WriteString(fmt.Sprintf("%v", ij.Errors))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(ij.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(ij.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := ij.FinishedAt; v != nil {
+		builder.WriteString("finished_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// ImportJobs is a parsable slice of ImportJob.
+type ImportJobs []*ImportJob
@@ -0,0 +1,241 @@
+// Code generated by ent, DO NOT EDIT.
+
+package importjob
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the importjob type in the database.
+	Label = "import_job"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldStatus holds the string denoting the status field in the database.
+	FieldStatus = "status"
+	// FieldMode holds the string denoting the mode field in the database.
+	FieldMode = "mode"
+	// FieldFormat holds the string denoting the format field in the database.
+	FieldFormat = "format"
+	// FieldTotalRows holds the string denoting the total_rows field in the database.
+	FieldTotalRows = "total_rows"
+	// FieldProcessedRows holds the string denoting the processed_rows field in the database.
+	FieldProcessedRows = "processed_rows"
+	// FieldCreatedRows holds the string denoting the created_rows field in the database.
+	FieldCreatedRows = "created_rows"
+	// FieldUpdatedRows holds the string denoting the updated_rows field in the database.
+	FieldUpdatedRows = "updated_rows"
+	// FieldFailedRows holds the string denoting the failed_rows field in the database.
+	FieldFailedRows = "failed_rows"
+	// FieldErrors holds the string denoting the errors field in the database.
+	FieldErrors = "errors"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
+	FieldUpdatedAt = "updated_at"
+	// FieldFinishedAt holds the string denoting the finished_at field in the database.
+	FieldFinishedAt = "finished_at"
+	// Table holds the table name of the importjob in the database.
+	Table = "import_jobs"
+)
+
+// Columns holds all SQL columns for importjob fields.
+var Columns = []string{
+	FieldID,
+	FieldStatus,
+	FieldMode,
+	FieldFormat,
+	FieldTotalRows,
+	FieldProcessedRows,
+	FieldCreatedRows,
+	FieldUpdatedRows,
+	FieldFailedRows,
+	FieldErrors,
+	FieldCreatedAt,
+	FieldUpdatedAt,
+	FieldFinishedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultTotalRows holds the default value on creation for the "total_rows" field.
+	DefaultTotalRows int
+	// DefaultProcessedRows holds the default value on creation for the "processed_rows" field.
+	DefaultProcessedRows int
+	// DefaultCreatedRows holds the default value on creation for the "created_rows" field.
+	DefaultCreatedRows int
+	// DefaultUpdatedRows holds the default value on creation for the "updated_rows" field.
+	DefaultUpdatedRows int
+	// DefaultFailedRows holds the default value on creation for the "failed_rows" field.
+	DefaultFailedRows int
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
+	DefaultUpdatedAt func() time.Time
+	// UpdateDefaultUpdatedAt holds the default value on update for the "updated_at" field.
+	UpdateDefaultUpdatedAt func() time.Time
+)
+
+// Status defines the type for the "status" enum field.
+type Status string
+
+// StatusPending is the default value of the Status enum.
+const DefaultStatus = StatusPending
+
+// Status values.
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// StatusValidator is a validator for the "status" field enum values. It is called by the builders before save.
+func StatusValidator(s Status) error {
+	switch s {
+	case StatusPending, StatusRunning, StatusSucceeded, StatusFailed:
+		return nil
+	default:
+		return fmt.Errorf("importjob: invalid enum value for status field: %q", s)
+	}
+}
+
+// Mode defines the type for the "mode" enum field.
+type Mode string
+
+// Mode values.
+const (
+	ModeAtomic     Mode = "atomic"
+	ModeBestEffort Mode = "best_effort"
+)
+
+func (m Mode) String() string {
+	return string(m)
+}
+
+// ModeValidator is a validator for the "mode" field enum values. It is called by the builders before save.
+func ModeValidator(m Mode) error {
+	switch m {
+	case ModeAtomic, ModeBestEffort:
+		return nil
+	default:
+		return fmt.Errorf("importjob: invalid enum value for mode field: %q", m)
+	}
+}
+
+// OrderOption defines the ordering options for the ImportJob queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByStatus orders the results by the status field.
+func ByStatus(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStatus, opts...).ToFunc()
+}
+
+// ByMode orders the results by the mode field.
+func ByMode(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMode, opts...).ToFunc()
+}
+
+// ByFormat orders the results by the format field.
+func ByFormat(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFormat, opts...).ToFunc()
+}
+
+// ByTotalRows orders the results by the total_rows field.
+func ByTotalRows(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTotalRows, opts...).ToFunc()
+}
+
+// ByProcessedRows orders the results by the processed_rows field.
+func ByProcessedRows(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldProcessedRows, opts...).ToFunc()
+}
+
+// ByCreatedRows orders the results by the created_rows field.
+func ByCreatedRows(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedRows, opts...).ToFunc()
+}
+
+// ByUpdatedRows orders the results by the updated_rows field.
+func ByUpdatedRows(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUpdatedRows, opts...).ToFunc()
+}
+
+// ByFailedRows orders the results by the failed_rows field.
+func ByFailedRows(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFailedRows, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
+
+// ByUpdatedAt orders the results by the updated_at field.
+func ByUpdatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUpdatedAt, opts...).ToFunc()
+}
+
+// ByFinishedAt orders the results by the finished_at field.
+func ByFinishedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFinishedAt, opts...).ToFunc()
+}
+
+// MarshalGQL implements graphql.Marshaler interface.
+func (e Status) MarshalGQL(w io.Writer) {
+	io.WriteString(w, strconv.Quote(e.String()))
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler interface.
+func (e *Status) UnmarshalGQL(val interface{}) error {
+	str, ok := val.(string)
+	if !ok {
+		return fmt.Errorf("enum %T must be a string", val)
+	}
+	*e = Status(str)
+	if err := StatusValidator(*e); err != nil {
+		return fmt.Errorf("%s is not a valid Status", str)
+	}
+	return nil
+}
+
+// MarshalGQL implements graphql.Marshaler interface.
+func (e Mode) MarshalGQL(w io.Writer) {
+	io.WriteString(w, strconv.Quote(e.String()))
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler interface.
+func (e *Mode) UnmarshalGQL(val interface{}) error {
+	str, ok := val.(string)
+	if !ok {
+		return fmt.Errorf("enum %T must be a string", val)
+	}
+	*e = Mode(str)
+	if err := ModeValidator(*e); err != nil {
+		return fmt.Errorf("%s is not a valid Mode", str)
+	}
+	return nil
+}
@@ -0,0 +1,560 @@
+// Code generated by ent, DO NOT EDIT.
+
+package importjob
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/tonymj76/mytheresa-test/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldID, id))
+}
+
+// Format applies equality check predicate on the "format" field. It's identical to FormatEQ.
+func Format(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldFormat, v))
+}
+
+// TotalRows applies equality check predicate on the "total_rows" field. It's identical to TotalRowsEQ.
+func TotalRows(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldTotalRows, v))
+}
+
+// ProcessedRows applies equality check predicate on the "processed_rows" field. It's identical to ProcessedRowsEQ.
+func ProcessedRows(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldProcessedRows, v))
+}
+
+// CreatedRows applies equality check predicate on the "created_rows" field. It's identical to CreatedRowsEQ.
+func CreatedRows(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldCreatedRows, v))
+}
+
+// UpdatedRows applies equality check predicate on the "updated_rows" field. It's identical to UpdatedRowsEQ.
+func UpdatedRows(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldUpdatedRows, v))
+}
+
+// FailedRows applies equality check predicate on the "failed_rows" field. It's identical to FailedRowsEQ.
+func FailedRows(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldFailedRows, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// FinishedAt applies equality check predicate on the "finished_at" field. It's identical to FinishedAtEQ.
+func FinishedAt(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldFinishedAt, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v Status) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v Status) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...Status) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...Status) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// ModeEQ applies the EQ predicate on the "mode" field.
+func ModeEQ(v Mode) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldMode, v))
+}
+
+// ModeNEQ applies the NEQ predicate on the "mode" field.
+func ModeNEQ(v Mode) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldMode, v))
+}
+
+// ModeIn applies the In predicate on the "mode" field.
+func ModeIn(vs ...Mode) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldMode, vs...))
+}
+
+// ModeNotIn applies the NotIn predicate on the "mode" field.
+func ModeNotIn(vs ...Mode) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldMode, vs...))
+}
+
+// FormatEQ applies the EQ predicate on the "format" field.
+func FormatEQ(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldFormat, v))
+}
+
+// FormatNEQ applies the NEQ predicate on the "format" field.
+func FormatNEQ(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldFormat, v))
+}
+
+// FormatIn applies the In predicate on the "format" field.
+func FormatIn(vs ...string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldFormat, vs...))
+}
+
+// FormatNotIn applies the NotIn predicate on the "format" field.
+func FormatNotIn(vs ...string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldFormat, vs...))
+}
+
+// FormatGT applies the GT predicate on the "format" field.
+func FormatGT(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldFormat, v))
+}
+
+// FormatGTE applies the GTE predicate on the "format" field.
+func FormatGTE(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldFormat, v))
+}
+
+// FormatLT applies the LT predicate on the "format" field.
+func FormatLT(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldFormat, v))
+}
+
+// FormatLTE applies the LTE predicate on the "format" field.
+func FormatLTE(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldFormat, v))
+}
+
+// FormatContains applies the Contains predicate on the "format" field.
+func FormatContains(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldContains(FieldFormat, v))
+}
+
+// FormatHasPrefix applies the HasPrefix predicate on the "format" field.
+func FormatHasPrefix(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldHasPrefix(FieldFormat, v))
+}
+
+// FormatHasSuffix applies the HasSuffix predicate on the "format" field.
+func FormatHasSuffix(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldHasSuffix(FieldFormat, v))
+}
+
+// FormatEqualFold applies the EqualFold predicate on the "format" field.
+func FormatEqualFold(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEqualFold(FieldFormat, v))
+}
+
+// FormatContainsFold applies the ContainsFold predicate on the "format" field.
+func FormatContainsFold(v string) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldContainsFold(FieldFormat, v))
+}
+
+// TotalRowsEQ applies the EQ predicate on the "total_rows" field.
+func TotalRowsEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldTotalRows, v))
+}
+
+// TotalRowsNEQ applies the NEQ predicate on the "total_rows" field.
+func TotalRowsNEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldTotalRows, v))
+}
+
+// TotalRowsIn applies the In predicate on the "total_rows" field.
+func TotalRowsIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldTotalRows, vs...))
+}
+
+// TotalRowsNotIn applies the NotIn predicate on the "total_rows" field.
+func TotalRowsNotIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldTotalRows, vs...))
+}
+
+// TotalRowsGT applies the GT predicate on the "total_rows" field.
+func TotalRowsGT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldTotalRows, v))
+}
+
+// TotalRowsGTE applies the GTE predicate on the "total_rows" field.
+func TotalRowsGTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldTotalRows, v))
+}
+
+// TotalRowsLT applies the LT predicate on the "total_rows" field.
+func TotalRowsLT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldTotalRows, v))
+}
+
+// TotalRowsLTE applies the LTE predicate on the "total_rows" field.
+func TotalRowsLTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldTotalRows, v))
+}
+
+// ProcessedRowsEQ applies the EQ predicate on the "processed_rows" field.
+func ProcessedRowsEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldProcessedRows, v))
+}
+
+// ProcessedRowsNEQ applies the NEQ predicate on the "processed_rows" field.
+func ProcessedRowsNEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldProcessedRows, v))
+}
+
+// ProcessedRowsIn applies the In predicate on the "processed_rows" field.
+func ProcessedRowsIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldProcessedRows, vs...))
+}
+
+// ProcessedRowsNotIn applies the NotIn predicate on the "processed_rows" field.
+func ProcessedRowsNotIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldProcessedRows, vs...))
+}
+
+// ProcessedRowsGT applies the GT predicate on the "processed_rows" field.
+func ProcessedRowsGT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldProcessedRows, v))
+}
+
+// ProcessedRowsGTE applies the GTE predicate on the "processed_rows" field.
+func ProcessedRowsGTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldProcessedRows, v))
+}
+
+// ProcessedRowsLT applies the LT predicate on the "processed_rows" field.
+func ProcessedRowsLT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldProcessedRows, v))
+}
+
+// ProcessedRowsLTE applies the LTE predicate on the "processed_rows" field.
+func ProcessedRowsLTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldProcessedRows, v))
+}
+
+// CreatedRowsEQ applies the EQ predicate on the "created_rows" field.
+func CreatedRowsEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldCreatedRows, v))
+}
+
+// CreatedRowsNEQ applies the NEQ predicate on the "created_rows" field.
+func CreatedRowsNEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldCreatedRows, v))
+}
+
+// CreatedRowsIn applies the In predicate on the "created_rows" field.
+func CreatedRowsIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldCreatedRows, vs...))
+}
+
+// CreatedRowsNotIn applies the NotIn predicate on the "created_rows" field.
+func CreatedRowsNotIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldCreatedRows, vs...))
+}
+
+// CreatedRowsGT applies the GT predicate on the "created_rows" field.
+func CreatedRowsGT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldCreatedRows, v))
+}
+
+// CreatedRowsGTE applies the GTE predicate on the "created_rows" field.
+func CreatedRowsGTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldCreatedRows, v))
+}
+
+// CreatedRowsLT applies the LT predicate on the "created_rows" field.
+func CreatedRowsLT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldCreatedRows, v))
+}
+
+// CreatedRowsLTE applies the LTE predicate on the "created_rows" field.
+func CreatedRowsLTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldCreatedRows, v))
+}
+
+// UpdatedRowsEQ applies the EQ predicate on the "updated_rows" field.
+func UpdatedRowsEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldUpdatedRows, v))
+}
+
+// UpdatedRowsNEQ applies the NEQ predicate on the "updated_rows" field.
+func UpdatedRowsNEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldUpdatedRows, v))
+}
+
+// UpdatedRowsIn applies the In predicate on the "updated_rows" field.
+func UpdatedRowsIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldUpdatedRows, vs...))
+}
+
+// UpdatedRowsNotIn applies the NotIn predicate on the "updated_rows" field.
+func UpdatedRowsNotIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldUpdatedRows, vs...))
+}
+
+// UpdatedRowsGT applies the GT predicate on the "updated_rows" field.
+func UpdatedRowsGT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldUpdatedRows, v))
+}
+
+// UpdatedRowsGTE applies the GTE predicate on the "updated_rows" field.
+func UpdatedRowsGTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldUpdatedRows, v))
+}
+
+// UpdatedRowsLT applies the LT predicate on the "updated_rows" field.
+func UpdatedRowsLT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldUpdatedRows, v))
+}
+
+// UpdatedRowsLTE applies the LTE predicate on the "updated_rows" field.
+func UpdatedRowsLTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldUpdatedRows, v))
+}
+
+// FailedRowsEQ applies the EQ predicate on the "failed_rows" field.
+func FailedRowsEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldFailedRows, v))
+}
+
+// FailedRowsNEQ applies the NEQ predicate on the "failed_rows" field.
+func FailedRowsNEQ(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldFailedRows, v))
+}
+
+// FailedRowsIn applies the In predicate on the "failed_rows" field.
+func FailedRowsIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldFailedRows, vs...))
+}
+
+// FailedRowsNotIn applies the NotIn predicate on the "failed_rows" field.
+func FailedRowsNotIn(vs ...int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldFailedRows, vs...))
+}
+
+// FailedRowsGT applies the GT predicate on the "failed_rows" field.
+func FailedRowsGT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldFailedRows, v))
+}
+
+// FailedRowsGTE applies the GTE predicate on the "failed_rows" field.
+func FailedRowsGTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldFailedRows, v))
+}
+
+// FailedRowsLT applies the LT predicate on the "failed_rows" field.
+func FailedRowsLT(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldFailedRows, v))
+}
+
+// FailedRowsLTE applies the LTE predicate on the "failed_rows" field.
+func FailedRowsLTE(v int) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldFailedRows, v))
+}
+
+// ErrorsIsNil applies the IsNil predicate on the "errors" field.
+func ErrorsIsNil() predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIsNull(FieldErrors))
+}
+
+// ErrorsNotNil applies the NotNil predicate on the "errors" field.
+func ErrorsNotNil() predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotNull(FieldErrors))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// FinishedAtEQ applies the EQ predicate on the "finished_at" field.
+func FinishedAtEQ(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldEQ(FieldFinishedAt, v))
+}
+
+// FinishedAtNEQ applies the NEQ predicate on the "finished_at" field.
+func FinishedAtNEQ(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNEQ(FieldFinishedAt, v))
+}
+
+// FinishedAtIn applies the In predicate on the "finished_at" field.
+func FinishedAtIn(vs ...time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIn(FieldFinishedAt, vs...))
+}
+
+// FinishedAtNotIn applies the NotIn predicate on the "finished_at" field.
+func FinishedAtNotIn(vs ...time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotIn(FieldFinishedAt, vs...))
+}
+
+// FinishedAtGT applies the GT predicate on the "finished_at" field.
+func FinishedAtGT(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGT(FieldFinishedAt, v))
+}
+
+// FinishedAtGTE applies the GTE predicate on the "finished_at" field.
+func FinishedAtGTE(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldGTE(FieldFinishedAt, v))
+}
+
+// FinishedAtLT applies the LT predicate on the "finished_at" field.
+func FinishedAtLT(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLT(FieldFinishedAt, v))
+}
+
+// FinishedAtLTE applies the LTE predicate on the "finished_at" field.
+func FinishedAtLTE(v time.Time) predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldLTE(FieldFinishedAt, v))
+}
+
+// FinishedAtIsNil applies the IsNil predicate on the "finished_at" field.
+func FinishedAtIsNil() predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldIsNull(FieldFinishedAt))
+}
+
+// FinishedAtNotNil applies the NotNil predicate on the "finished_at" field.
+func FinishedAtNotNil() predicate.ImportJob {
+	return predicate.ImportJob(sql.FieldNotNull(FieldFinishedAt))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.ImportJob) predicate.ImportJob {
+	return predicate.ImportJob(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.ImportJob) predicate.ImportJob {
+	return predicate.ImportJob(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.ImportJob) predicate.ImportJob {
+	return predicate.ImportJob(sql.NotPredicates(p))
+}
@@ -0,0 +1,1265 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/tonymj76/mytheresa-test/ent/importjob"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+// ImportJobCreate is the builder for creating a ImportJob entity.
+type ImportJobCreate struct {
+	config
+	mutation *ImportJobMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetStatus sets the "status" field.
+func (ijc *ImportJobCreate) SetStatus(i importjob.Status) *ImportJobCreate {
+	ijc.mutation.SetStatus(i)
+	return ijc
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableStatus(i *importjob.Status) *ImportJobCreate {
+	if i != nil {
+		ijc.SetStatus(*i)
+	}
+	return ijc
+}
+
+// SetMode sets the "mode" field.
+func (ijc *ImportJobCreate) SetMode(i importjob.Mode) *ImportJobCreate {
+	ijc.mutation.SetMode(i)
+	return ijc
+}
+
+// SetFormat sets the "format" field.
+func (ijc *ImportJobCreate) SetFormat(s string) *ImportJobCreate {
+	ijc.mutation.SetFormat(s)
+	return ijc
+}
+
+// SetTotalRows sets the "total_rows" field.
+func (ijc *ImportJobCreate) SetTotalRows(i int) *ImportJobCreate {
+	ijc.mutation.SetTotalRows(i)
+	return ijc
+}
+
+// SetNillableTotalRows sets the "total_rows" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableTotalRows(i *int) *ImportJobCreate {
+	if i != nil {
+		ijc.SetTotalRows(*i)
+	}
+	return ijc
+}
+
+// SetProcessedRows sets the "processed_rows" field.
+func (ijc *ImportJobCreate) SetProcessedRows(i int) *ImportJobCreate {
+	ijc.mutation.SetProcessedRows(i)
+	return ijc
+}
+
+// SetNillableProcessedRows sets the "processed_rows" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableProcessedRows(i *int) *ImportJobCreate {
+	if i != nil {
+		ijc.SetProcessedRows(*i)
+	}
+	return ijc
+}
+
+// SetCreatedRows sets the "created_rows" field.
+func (ijc *ImportJobCreate) SetCreatedRows(i int) *ImportJobCreate {
+	ijc.mutation.SetCreatedRows(i)
+	return ijc
+}
+
+// SetNillableCreatedRows sets the "created_rows" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableCreatedRows(i *int) *ImportJobCreate {
+	if i != nil {
+		ijc.SetCreatedRows(*i)
+	}
+	return ijc
+}
+
+// SetUpdatedRows sets the "updated_rows" field.
+func (ijc *ImportJobCreate) SetUpdatedRows(i int) *ImportJobCreate {
+	ijc.mutation.SetUpdatedRows(i)
+	return ijc
+}
+
+// SetNillableUpdatedRows sets the "updated_rows" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableUpdatedRows(i *int) *ImportJobCreate {
+	if i != nil {
+		ijc.SetUpdatedRows(*i)
+	}
+	return ijc
+}
+
+// SetFailedRows sets the "failed_rows" field.
+func (ijc *ImportJobCreate) SetFailedRows(i int) *ImportJobCreate {
+	ijc.mutation.SetFailedRows(i)
+	return ijc
+}
+
+// SetNillableFailedRows sets the "failed_rows" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableFailedRows(i *int) *ImportJobCreate {
+	if i != nil {
+		ijc.SetFailedRows(*i)
+	}
+	return ijc
+}
+
+// SetErrors sets the "errors" field.
+func (ijc *ImportJobCreate) SetErrors(me []models.RowError) *ImportJobCreate {
+	ijc.mutation.SetErrors(me)
+	return ijc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ijc *ImportJobCreate) SetCreatedAt(t time.Time) *ImportJobCreate {
+	ijc.mutation.SetCreatedAt(t)
+	return ijc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableCreatedAt(t *time.Time) *ImportJobCreate {
+	if t != nil {
+		ijc.SetCreatedAt(*t)
+	}
+	return ijc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ijc *ImportJobCreate) SetUpdatedAt(t time.Time) *ImportJobCreate {
+	ijc.mutation.SetUpdatedAt(t)
+	return ijc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableUpdatedAt(t *time.Time) *ImportJobCreate {
+	if t != nil {
+		ijc.SetUpdatedAt(*t)
+	}
+	return ijc
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (ijc *ImportJobCreate) SetFinishedAt(t time.Time) *ImportJobCreate {
+	ijc.mutation.SetFinishedAt(t)
+	return ijc
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (ijc *ImportJobCreate) SetNillableFinishedAt(t *time.Time) *ImportJobCreate {
+	if t != nil {
+		ijc.SetFinishedAt(*t)
+	}
+	return ijc
+}
+
+// Mutation returns the ImportJobMutation object of the builder.
+func (ijc *ImportJobCreate) Mutation() *ImportJobMutation {
+	return ijc.mutation
+}
+
+// Save creates the ImportJob in the database.
+func (ijc *ImportJobCreate) Save(ctx context.Context) (*ImportJob, error) {
+	ijc.defaults()
+	return withHooks(ctx, ijc.sqlSave, ijc.mutation, ijc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ijc *ImportJobCreate) SaveX(ctx context.Context) *ImportJob {
+	v, err := ijc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ijc *ImportJobCreate) Exec(ctx context.Context) error {
+	_, err := ijc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ijc *ImportJobCreate) ExecX(ctx context.Context) {
+	if err := ijc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ijc *ImportJobCreate) defaults() {
+	if _, ok := ijc.mutation.Status(); !ok {
+		v := importjob.DefaultStatus
+		ijc.mutation.SetStatus(v)
+	}
+	if _, ok := ijc.mutation.TotalRows(); !ok {
+		v := importjob.DefaultTotalRows
+		ijc.mutation.SetTotalRows(v)
+	}
+	if _, ok := ijc.mutation.ProcessedRows(); !ok {
+		v := importjob.DefaultProcessedRows
+		ijc.mutation.SetProcessedRows(v)
+	}
+	if _, ok := ijc.mutation.CreatedRows(); !ok {
+		v := importjob.DefaultCreatedRows
+		ijc.mutation.SetCreatedRows(v)
+	}
+	if _, ok := ijc.mutation.UpdatedRows(); !ok {
+		v := importjob.DefaultUpdatedRows
+		ijc.mutation.SetUpdatedRows(v)
+	}
+	if _, ok := ijc.mutation.FailedRows(); !ok {
+		v := importjob.DefaultFailedRows
+		ijc.mutation.SetFailedRows(v)
+	}
+	if _, ok := ijc.mutation.CreatedAt(); !ok {
+		v := importjob.DefaultCreatedAt()
+		ijc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := ijc.mutation.UpdatedAt(); !ok {
+		v := importjob.DefaultUpdatedAt()
+		ijc.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ijc *ImportJobCreate) check() error {
+	if _, ok := ijc.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "ImportJob.status"`)}
+	}
+	if v, ok := ijc.mutation.Status(); ok {
+		if err := importjob.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "ImportJob.status": %w`, err)}
+		}
+	}
+	if _, ok := ijc.mutation.Mode(); !ok {
+		return &ValidationError{Name: "mode", err: errors.New(`ent: missing required field "ImportJob.mode"`)}
+	}
+	if v, ok := ijc.mutation.Mode(); ok {
+		if err := importjob.ModeValidator(v); err != nil {
+			return &ValidationError{Name: "mode", err: fmt.Errorf(`ent: validator failed for field "ImportJob.mode": %w`, err)}
+		}
+	}
+	if _, ok := ijc.mutation.Format(); !ok {
+		return &ValidationError{Name: "format", err: errors.New(`ent: missing required field "ImportJob.format"`)}
+	}
+	if _, ok := ijc.mutation.TotalRows(); !ok {
+		return &ValidationError{Name: "total_rows", err: errors.New(`ent: missing required field "ImportJob.total_rows"`)}
+	}
+	if _, ok := ijc.mutation.ProcessedRows(); !ok {
+		return &ValidationError{Name: "processed_rows", err: errors.New(`ent: missing required field "ImportJob.processed_rows"`)}
+	}
+	if _, ok := ijc.mutation.CreatedRows(); !ok {
+		return &ValidationError{Name: "created_rows", err: errors.New(`ent: missing required field "ImportJob.created_rows"`)}
+	}
+	if _, ok := ijc.mutation.UpdatedRows(); !ok {
+		return &ValidationError{Name: "updated_rows", err: errors.New(`ent: missing required field "ImportJob.updated_rows"`)}
+	}
+	if _, ok := ijc.mutation.FailedRows(); !ok {
+		return &ValidationError{Name: "failed_rows", err: errors.New(`ent: missing required field "ImportJob.failed_rows"`)}
+	}
+	if _, ok := ijc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ImportJob.created_at"`)}
+	}
+	if _, ok := ijc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "ImportJob.updated_at"`)}
+	}
+	return nil
+}
+
+func (ijc *ImportJobCreate) sqlSave(ctx context.Context) (*ImportJob, error) {
+	if err := ijc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ijc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ijc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ijc.mutation.id = &_node.ID
+	ijc.mutation.done = true
+	return _node, nil
+}
+
+func (ijc *ImportJobCreate) createSpec() (*ImportJob, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ImportJob{config: ijc.config}
+		_spec = sqlgraph.NewCreateSpec(importjob.Table, sqlgraph.NewFieldSpec(importjob.FieldID, field.TypeInt))
+	)
+	_spec.OnConflict = ijc.conflict
+	if value, ok := ijc.mutation.Status(); ok {
+		_spec.SetField(importjob.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := ijc.mutation.Mode(); ok {
+		_spec.SetField(importjob.FieldMode, field.TypeEnum, value)
+		_node.Mode = value
+	}
+	if value, ok := ijc.mutation.Format(); ok {
+		_spec.SetField(importjob.FieldFormat, field.TypeString, value)
+		_node.Format = value
+	}
+	if value, ok := ijc.mutation.TotalRows(); ok {
+		_spec.SetField(importjob.FieldTotalRows, field.TypeInt, value)
+		_node.TotalRows = value
+	}
+	if value, ok := ijc.mutation.ProcessedRows(); ok {
+		_spec.SetField(importjob.FieldProcessedRows, field.TypeInt, value)
+		_node.ProcessedRows = value
+	}
+	if value, ok := ijc.mutation.CreatedRows(); ok {
+		_spec.SetField(importjob.FieldCreatedRows, field.TypeInt, value)
+		_node.CreatedRows = value
+	}
+	if value, ok := ijc.mutation.UpdatedRows(); ok {
+		_spec.SetField(importjob.FieldUpdatedRows, field.TypeInt, value)
+		_node.UpdatedRows = value
+	}
+	if value, ok := ijc.mutation.FailedRows(); ok {
+		_spec.SetField(importjob.FieldFailedRows, field.TypeInt, value)
+		_node.FailedRows = value
+	}
+	if value, ok := ijc.mutation.Errors(); ok {
+		_spec.SetField(importjob.FieldErrors, field.TypeJSON, value)
+		_node.Errors = value
+	}
+	if value, ok := ijc.mutation.CreatedAt(); ok {
+		_spec.SetField(importjob.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := ijc.mutation.UpdatedAt(); ok {
+		_spec.SetField(importjob.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := ijc.mutation.FinishedAt(); ok {
+		_spec.SetField(importjob.FieldFinishedAt, field.TypeTime, value)
+		_node.FinishedAt = &value
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.ImportJob.Create().
+//		SetStatus(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ImportJobUpsert) {
+//			SetStatus(v+v).
+//		}).
+//		Exec(ctx)
+func (ijc *ImportJobCreate) OnConflict(opts ...sql.ConflictOption) *ImportJobUpsertOne {
+	ijc.conflict = opts
+	return &ImportJobUpsertOne{
+		create: ijc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.ImportJob.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (ijc *ImportJobCreate) OnConflictColumns(columns ...string) *ImportJobUpsertOne {
+	ijc.conflict = append(ijc.conflict, sql.ConflictColumns(columns...))
+	return &ImportJobUpsertOne{
+		create: ijc,
+	}
+}
+
+type (
+	// ImportJobUpsertOne is the builder for "upsert"-ing
+	//  one ImportJob node.
+	ImportJobUpsertOne struct {
+		create *ImportJobCreate
+	}
+
+	// ImportJobUpsert is the "OnConflict" setter.
+	ImportJobUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetStatus sets the "status" field.
+func (u *ImportJobUpsert) SetStatus(v importjob.Status) *ImportJobUpsert {
+	u.Set(importjob.FieldStatus, v)
+	return u
+}
+
+// UpdateStatus sets the "status" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateStatus() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldStatus)
+	return u
+}
+
+// SetMode sets the "mode" field.
+func (u *ImportJobUpsert) SetMode(v importjob.Mode) *ImportJobUpsert {
+	u.Set(importjob.FieldMode, v)
+	return u
+}
+
+// UpdateMode sets the "mode" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateMode() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldMode)
+	return u
+}
+
+// SetFormat sets the "format" field.
+func (u *ImportJobUpsert) SetFormat(v string) *ImportJobUpsert {
+	u.Set(importjob.FieldFormat, v)
+	return u
+}
+
+// UpdateFormat sets the "format" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateFormat() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldFormat)
+	return u
+}
+
+// SetTotalRows sets the "total_rows" field.
+func (u *ImportJobUpsert) SetTotalRows(v int) *ImportJobUpsert {
+	u.Set(importjob.FieldTotalRows, v)
+	return u
+}
+
+// UpdateTotalRows sets the "total_rows" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateTotalRows() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldTotalRows)
+	return u
+}
+
+// AddTotalRows adds v to the "total_rows" field.
+func (u *ImportJobUpsert) AddTotalRows(v int) *ImportJobUpsert {
+	u.Add(importjob.FieldTotalRows, v)
+	return u
+}
+
+// SetProcessedRows sets the "processed_rows" field.
+func (u *ImportJobUpsert) SetProcessedRows(v int) *ImportJobUpsert {
+	u.Set(importjob.FieldProcessedRows, v)
+	return u
+}
+
+// UpdateProcessedRows sets the "processed_rows" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateProcessedRows() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldProcessedRows)
+	return u
+}
+
+// AddProcessedRows adds v to the "processed_rows" field.
+func (u *ImportJobUpsert) AddProcessedRows(v int) *ImportJobUpsert {
+	u.Add(importjob.FieldProcessedRows, v)
+	return u
+}
+
+// SetCreatedRows sets the "created_rows" field.
+func (u *ImportJobUpsert) SetCreatedRows(v int) *ImportJobUpsert {
+	u.Set(importjob.FieldCreatedRows, v)
+	return u
+}
+
+// UpdateCreatedRows sets the "created_rows" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateCreatedRows() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldCreatedRows)
+	return u
+}
+
+// AddCreatedRows adds v to the "created_rows" field.
+func (u *ImportJobUpsert) AddCreatedRows(v int) *ImportJobUpsert {
+	u.Add(importjob.FieldCreatedRows, v)
+	return u
+}
+
+// SetUpdatedRows sets the "updated_rows" field.
+func (u *ImportJobUpsert) SetUpdatedRows(v int) *ImportJobUpsert {
+	u.Set(importjob.FieldUpdatedRows, v)
+	return u
+}
+
+// UpdateUpdatedRows sets the "updated_rows" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateUpdatedRows() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldUpdatedRows)
+	return u
+}
+
+// AddUpdatedRows adds v to the "updated_rows" field.
+func (u *ImportJobUpsert) AddUpdatedRows(v int) *ImportJobUpsert {
+	u.Add(importjob.FieldUpdatedRows, v)
+	return u
+}
+
+// SetFailedRows sets the "failed_rows" field.
+func (u *ImportJobUpsert) SetFailedRows(v int) *ImportJobUpsert {
+	u.Set(importjob.FieldFailedRows, v)
+	return u
+}
+
+// UpdateFailedRows sets the "failed_rows" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateFailedRows() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldFailedRows)
+	return u
+}
+
+// AddFailedRows adds v to the "failed_rows" field.
+func (u *ImportJobUpsert) AddFailedRows(v int) *ImportJobUpsert {
+	u.Add(importjob.FieldFailedRows, v)
+	return u
+}
+
+// SetErrors sets the "errors" field.
+func (u *ImportJobUpsert) SetErrors(v []models.RowError) *ImportJobUpsert {
+	u.Set(importjob.FieldErrors, v)
+	return u
+}
+
+// UpdateErrors sets the "errors" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateErrors() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldErrors)
+	return u
+}
+
+// ClearErrors clears the value of the "errors" field.
+func (u *ImportJobUpsert) ClearErrors() *ImportJobUpsert {
+	u.SetNull(importjob.FieldErrors)
+	return u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ImportJobUpsert) SetUpdatedAt(v time.Time) *ImportJobUpsert {
+	u.Set(importjob.FieldUpdatedAt, v)
+	return u
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateUpdatedAt() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldUpdatedAt)
+	return u
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (u *ImportJobUpsert) SetFinishedAt(v time.Time) *ImportJobUpsert {
+	u.Set(importjob.FieldFinishedAt, v)
+	return u
+}
+
+// UpdateFinishedAt sets the "finished_at" field to the value that was provided on create.
+func (u *ImportJobUpsert) UpdateFinishedAt() *ImportJobUpsert {
+	u.SetExcluded(importjob.FieldFinishedAt)
+	return u
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (u *ImportJobUpsert) ClearFinishedAt() *ImportJobUpsert {
+	u.SetNull(importjob.FieldFinishedAt)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.ImportJob.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ImportJobUpsertOne) UpdateNewValues() *ImportJobUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.CreatedAt(); exists {
+			s.SetIgnore(importjob.FieldCreatedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.ImportJob.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *ImportJobUpsertOne) Ignore() *ImportJobUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ImportJobUpsertOne) DoNothing() *ImportJobUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ImportJobCreate.OnConflict
+// documentation for more info.
+func (u *ImportJobUpsertOne) Update(set func(*ImportJobUpsert)) *ImportJobUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ImportJobUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetStatus sets the "status" field.
+func (u *ImportJobUpsertOne) SetStatus(v importjob.Status) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetStatus(v)
+	})
+}
+
+// UpdateStatus sets the "status" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateStatus() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateStatus()
+	})
+}
+
+// SetMode sets the "mode" field.
+func (u *ImportJobUpsertOne) SetMode(v importjob.Mode) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetMode(v)
+	})
+}
+
+// UpdateMode sets the "mode" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateMode() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateMode()
+	})
+}
+
+// SetFormat sets the "format" field.
+func (u *ImportJobUpsertOne) SetFormat(v string) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetFormat(v)
+	})
+}
+
+// UpdateFormat sets the "format" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateFormat() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateFormat()
+	})
+}
+
+// SetTotalRows sets the "total_rows" field.
+func (u *ImportJobUpsertOne) SetTotalRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetTotalRows(v)
+	})
+}
+
+// AddTotalRows adds v to the "total_rows" field.
+func (u *ImportJobUpsertOne) AddTotalRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddTotalRows(v)
+	})
+}
+
+// UpdateTotalRows sets the "total_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateTotalRows() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateTotalRows()
+	})
+}
+
+// SetProcessedRows sets the "processed_rows" field.
+func (u *ImportJobUpsertOne) SetProcessedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetProcessedRows(v)
+	})
+}
+
+// AddProcessedRows adds v to the "processed_rows" field.
+func (u *ImportJobUpsertOne) AddProcessedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddProcessedRows(v)
+	})
+}
+
+// UpdateProcessedRows sets the "processed_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateProcessedRows() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateProcessedRows()
+	})
+}
+
+// SetCreatedRows sets the "created_rows" field.
+func (u *ImportJobUpsertOne) SetCreatedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetCreatedRows(v)
+	})
+}
+
+// AddCreatedRows adds v to the "created_rows" field.
+func (u *ImportJobUpsertOne) AddCreatedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddCreatedRows(v)
+	})
+}
+
+// UpdateCreatedRows sets the "created_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateCreatedRows() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateCreatedRows()
+	})
+}
+
+// SetUpdatedRows sets the "updated_rows" field.
+func (u *ImportJobUpsertOne) SetUpdatedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetUpdatedRows(v)
+	})
+}
+
+// AddUpdatedRows adds v to the "updated_rows" field.
+func (u *ImportJobUpsertOne) AddUpdatedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddUpdatedRows(v)
+	})
+}
+
+// UpdateUpdatedRows sets the "updated_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateUpdatedRows() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateUpdatedRows()
+	})
+}
+
+// SetFailedRows sets the "failed_rows" field.
+func (u *ImportJobUpsertOne) SetFailedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetFailedRows(v)
+	})
+}
+
+// AddFailedRows adds v to the "failed_rows" field.
+func (u *ImportJobUpsertOne) AddFailedRows(v int) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddFailedRows(v)
+	})
+}
+
+// UpdateFailedRows sets the "failed_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateFailedRows() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateFailedRows()
+	})
+}
+
+// SetErrors sets the "errors" field.
+func (u *ImportJobUpsertOne) SetErrors(v []models.RowError) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetErrors(v)
+	})
+}
+
+// UpdateErrors sets the "errors" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateErrors() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateErrors()
+	})
+}
+
+// ClearErrors clears the value of the "errors" field.
+func (u *ImportJobUpsertOne) ClearErrors() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.ClearErrors()
+	})
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ImportJobUpsertOne) SetUpdatedAt(v time.Time) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetUpdatedAt(v)
+	})
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateUpdatedAt() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateUpdatedAt()
+	})
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (u *ImportJobUpsertOne) SetFinishedAt(v time.Time) *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetFinishedAt(v)
+	})
+}
+
+// UpdateFinishedAt sets the "finished_at" field to the value that was provided on create.
+func (u *ImportJobUpsertOne) UpdateFinishedAt() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateFinishedAt()
+	})
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (u *ImportJobUpsertOne) ClearFinishedAt() *ImportJobUpsertOne {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.ClearFinishedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *ImportJobUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for ImportJobCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ImportJobUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *ImportJobUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *ImportJobUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// ImportJobCreateBulk is the builder for creating many ImportJob entities in bulk.
+type ImportJobCreateBulk struct {
+	config
+	err      error
+	builders []*ImportJobCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the ImportJob entities in the database.
+func (ijcb *ImportJobCreateBulk) Save(ctx context.Context) ([]*ImportJob, error) {
+	if ijcb.err != nil {
+		return nil, ijcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(ijcb.builders))
+	nodes := make([]*ImportJob, len(ijcb.builders))
+	mutators := make([]Mutator, len(ijcb.builders))
+	for i := range ijcb.builders {
+		func(i int, root context.Context) {
+			builder := ijcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ImportJobMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, ijcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = ijcb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, ijcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, ijcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ijcb *ImportJobCreateBulk) SaveX(ctx context.Context) []*ImportJob {
+	v, err := ijcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ijcb *ImportJobCreateBulk) Exec(ctx context.Context) error {
+	_, err := ijcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ijcb *ImportJobCreateBulk) ExecX(ctx context.Context) {
+	if err := ijcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.ImportJob.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ImportJobUpsert) {
+//			SetStatus(v+v).
+//		}).
+//		Exec(ctx)
+func (ijcb *ImportJobCreateBulk) OnConflict(opts ...sql.ConflictOption) *ImportJobUpsertBulk {
+	ijcb.conflict = opts
+	return &ImportJobUpsertBulk{
+		create: ijcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.ImportJob.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (ijcb *ImportJobCreateBulk) OnConflictColumns(columns ...string) *ImportJobUpsertBulk {
+	ijcb.conflict = append(ijcb.conflict, sql.ConflictColumns(columns...))
+	return &ImportJobUpsertBulk{
+		create: ijcb,
+	}
+}
+
+// ImportJobUpsertBulk is the builder for "upsert"-ing
+// a bulk of ImportJob nodes.
+type ImportJobUpsertBulk struct {
+	create *ImportJobCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.ImportJob.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ImportJobUpsertBulk) UpdateNewValues() *ImportJobUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.CreatedAt(); exists {
+				s.SetIgnore(importjob.FieldCreatedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.ImportJob.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *ImportJobUpsertBulk) Ignore() *ImportJobUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ImportJobUpsertBulk) DoNothing() *ImportJobUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ImportJobCreateBulk.OnConflict
+// documentation for more info.
+func (u *ImportJobUpsertBulk) Update(set func(*ImportJobUpsert)) *ImportJobUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ImportJobUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetStatus sets the "status" field.
+func (u *ImportJobUpsertBulk) SetStatus(v importjob.Status) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetStatus(v)
+	})
+}
+
+// UpdateStatus sets the "status" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateStatus() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateStatus()
+	})
+}
+
+// SetMode sets the "mode" field.
+func (u *ImportJobUpsertBulk) SetMode(v importjob.Mode) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetMode(v)
+	})
+}
+
+// UpdateMode sets the "mode" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateMode() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateMode()
+	})
+}
+
+// SetFormat sets the "format" field.
+func (u *ImportJobUpsertBulk) SetFormat(v string) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetFormat(v)
+	})
+}
+
+// UpdateFormat sets the "format" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateFormat() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateFormat()
+	})
+}
+
+// SetTotalRows sets the "total_rows" field.
+func (u *ImportJobUpsertBulk) SetTotalRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetTotalRows(v)
+	})
+}
+
+// AddTotalRows adds v to the "total_rows" field.
+func (u *ImportJobUpsertBulk) AddTotalRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddTotalRows(v)
+	})
+}
+
+// UpdateTotalRows sets the "total_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateTotalRows() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateTotalRows()
+	})
+}
+
+// SetProcessedRows sets the "processed_rows" field.
+func (u *ImportJobUpsertBulk) SetProcessedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetProcessedRows(v)
+	})
+}
+
+// AddProcessedRows adds v to the "processed_rows" field.
+func (u *ImportJobUpsertBulk) AddProcessedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddProcessedRows(v)
+	})
+}
+
+// UpdateProcessedRows sets the "processed_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateProcessedRows() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateProcessedRows()
+	})
+}
+
+// SetCreatedRows sets the "created_rows" field.
+func (u *ImportJobUpsertBulk) SetCreatedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetCreatedRows(v)
+	})
+}
+
+// AddCreatedRows adds v to the "created_rows" field.
+func (u *ImportJobUpsertBulk) AddCreatedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddCreatedRows(v)
+	})
+}
+
+// UpdateCreatedRows sets the "created_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateCreatedRows() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateCreatedRows()
+	})
+}
+
+// SetUpdatedRows sets the "updated_rows" field.
+func (u *ImportJobUpsertBulk) SetUpdatedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetUpdatedRows(v)
+	})
+}
+
+// AddUpdatedRows adds v to the "updated_rows" field.
+func (u *ImportJobUpsertBulk) AddUpdatedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddUpdatedRows(v)
+	})
+}
+
+// UpdateUpdatedRows sets the "updated_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateUpdatedRows() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateUpdatedRows()
+	})
+}
+
+// SetFailedRows sets the "failed_rows" field.
+func (u *ImportJobUpsertBulk) SetFailedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetFailedRows(v)
+	})
+}
+
+// AddFailedRows adds v to the "failed_rows" field.
+func (u *ImportJobUpsertBulk) AddFailedRows(v int) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.AddFailedRows(v)
+	})
+}
+
+// UpdateFailedRows sets the "failed_rows" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateFailedRows() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateFailedRows()
+	})
+}
+
+// SetErrors sets the "errors" field.
+func (u *ImportJobUpsertBulk) SetErrors(v []models.RowError) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetErrors(v)
+	})
+}
+
+// UpdateErrors sets the "errors" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateErrors() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateErrors()
+	})
+}
+
+// ClearErrors clears the value of the "errors" field.
+func (u *ImportJobUpsertBulk) ClearErrors() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.ClearErrors()
+	})
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ImportJobUpsertBulk) SetUpdatedAt(v time.Time) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetUpdatedAt(v)
+	})
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateUpdatedAt() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateUpdatedAt()
+	})
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (u *ImportJobUpsertBulk) SetFinishedAt(v time.Time) *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.SetFinishedAt(v)
+	})
+}
+
+// UpdateFinishedAt sets the "finished_at" field to the value that was provided on create.
+func (u *ImportJobUpsertBulk) UpdateFinishedAt() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.UpdateFinishedAt()
+	})
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (u *ImportJobUpsertBulk) ClearFinishedAt() *ImportJobUpsertBulk {
+	return u.Update(func(s *ImportJobUpsert) {
+		s.ClearFinishedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *ImportJobUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("ent: OnConflict was set for builder %d. Set it on the ImportJobCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for ImportJobCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ImportJobUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
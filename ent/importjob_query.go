@@ -0,0 +1,554 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/tonymj76/mytheresa-test/ent/importjob"
+	"github.com/tonymj76/mytheresa-test/ent/predicate"
+)
+
+// ImportJobQuery is the builder for querying ImportJob entities.
+type ImportJobQuery struct {
+	config
+	ctx        *QueryContext
+	order      []importjob.OrderOption
+	inters     []Interceptor
+	predicates []predicate.ImportJob
+	loadTotal  []func(context.Context, []*ImportJob) error
+	modifiers  []func(*sql.Selector)
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the ImportJobQuery builder.
+func (ijq *ImportJobQuery) Where(ps ...predicate.ImportJob) *ImportJobQuery {
+	ijq.predicates = append(ijq.predicates, ps...)
+	return ijq
+}
+
+// Limit the number of records to be returned by this query.
+func (ijq *ImportJobQuery) Limit(limit int) *ImportJobQuery {
+	ijq.ctx.Limit = &limit
+	return ijq
+}
+
+// Offset to start from.
+func (ijq *ImportJobQuery) Offset(offset int) *ImportJobQuery {
+	ijq.ctx.Offset = &offset
+	return ijq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (ijq *ImportJobQuery) Unique(unique bool) *ImportJobQuery {
+	ijq.ctx.Unique = &unique
+	return ijq
+}
+
+// Order specifies how the records should be ordered.
+func (ijq *ImportJobQuery) Order(o ...importjob.OrderOption) *ImportJobQuery {
+	ijq.order = append(ijq.order, o...)
+	return ijq
+}
+
+// First returns the first ImportJob entity from the query.
+// Returns a *NotFoundError when no ImportJob was found.
+func (ijq *ImportJobQuery) First(ctx context.Context) (*ImportJob, error) {
+	nodes, err := ijq.Limit(1).All(setContextOp(ctx, ijq.ctx, "First"))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{importjob.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (ijq *ImportJobQuery) FirstX(ctx context.Context) *ImportJob {
+	node, err := ijq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first ImportJob ID from the query.
+// Returns a *NotFoundError when no ImportJob ID was found.
+func (ijq *ImportJobQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = ijq.Limit(1).IDs(setContextOp(ctx, ijq.ctx, "FirstID")); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{importjob.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (ijq *ImportJobQuery) FirstIDX(ctx context.Context) int {
+	id, err := ijq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single ImportJob entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one ImportJob entity is found.
+// Returns a *NotFoundError when no ImportJob entities are found.
+func (ijq *ImportJobQuery) Only(ctx context.Context) (*ImportJob, error) {
+	nodes, err := ijq.Limit(2).All(setContextOp(ctx, ijq.ctx, "Only"))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{importjob.Label}
+	default:
+		return nil, &NotSingularError{importjob.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (ijq *ImportJobQuery) OnlyX(ctx context.Context) *ImportJob {
+	node, err := ijq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only ImportJob ID in the query.
+// Returns a *NotSingularError when more than one ImportJob ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (ijq *ImportJobQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = ijq.Limit(2).IDs(setContextOp(ctx, ijq.ctx, "OnlyID")); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{importjob.Label}
+	default:
+		err = &NotSingularError{importjob.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (ijq *ImportJobQuery) OnlyIDX(ctx context.Context) int {
+	id, err := ijq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of ImportJobs.
+func (ijq *ImportJobQuery) All(ctx context.Context) ([]*ImportJob, error) {
+	ctx = setContextOp(ctx, ijq.ctx, "All")
+	if err := ijq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*ImportJob, *ImportJobQuery]()
+	return withInterceptors[[]*ImportJob](ctx, ijq, qr, ijq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (ijq *ImportJobQuery) AllX(ctx context.Context) []*ImportJob {
+	nodes, err := ijq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of ImportJob IDs.
+func (ijq *ImportJobQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if ijq.ctx.Unique == nil && ijq.path != nil {
+		ijq.Unique(true)
+	}
+	ctx = setContextOp(ctx, ijq.ctx, "IDs")
+	if err = ijq.Select(importjob.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (ijq *ImportJobQuery) IDsX(ctx context.Context) []int {
+	ids, err := ijq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (ijq *ImportJobQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, ijq.ctx, "Count")
+	if err := ijq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, ijq, querierCount[*ImportJobQuery](), ijq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (ijq *ImportJobQuery) CountX(ctx context.Context) int {
+	count, err := ijq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (ijq *ImportJobQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, ijq.ctx, "Exist")
+	switch _, err := ijq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (ijq *ImportJobQuery) ExistX(ctx context.Context) bool {
+	exist, err := ijq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the ImportJobQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (ijq *ImportJobQuery) Clone() *ImportJobQuery {
+	if ijq == nil {
+		return nil
+	}
+	return &ImportJobQuery{
+		config:     ijq.config,
+		ctx:        ijq.ctx.Clone(),
+		order:      append([]importjob.OrderOption{}, ijq.order...),
+		inters:     append([]Interceptor{}, ijq.inters...),
+		predicates: append([]predicate.ImportJob{}, ijq.predicates...),
+		// clone intermediate query.
+		sql:  ijq.sql.Clone(),
+		path: ijq.path,
+	}
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		Status importjob.Status `json:"status,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.ImportJob.Query().
+//		GroupBy(importjob.FieldStatus).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (ijq *ImportJobQuery) GroupBy(field string, fields ...string) *ImportJobGroupBy {
+	ijq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &ImportJobGroupBy{build: ijq}
+	grbuild.flds = &ijq.ctx.Fields
+	grbuild.label = importjob.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		Status importjob.Status `json:"status,omitempty"`
+//	}
+//
+//	client.ImportJob.Query().
+//		Select(importjob.FieldStatus).
+//		Scan(ctx, &v)
+func (ijq *ImportJobQuery) Select(fields ...string) *ImportJobSelect {
+	ijq.ctx.Fields = append(ijq.ctx.Fields, fields...)
+	sbuild := &ImportJobSelect{ImportJobQuery: ijq}
+	sbuild.label = importjob.Label
+	sbuild.flds, sbuild.scan = &ijq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a ImportJobSelect configured with the given aggregations.
+func (ijq *ImportJobQuery) Aggregate(fns ...AggregateFunc) *ImportJobSelect {
+	return ijq.Select().Aggregate(fns...)
+}
+
+func (ijq *ImportJobQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range ijq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, ijq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range ijq.ctx.Fields {
+		if !importjob.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if ijq.path != nil {
+		prev, err := ijq.path(ctx)
+		if err != nil {
+			return err
+		}
+		ijq.sql = prev
+	}
+	return nil
+}
+
+func (ijq *ImportJobQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*ImportJob, error) {
+	var (
+		nodes = []*ImportJob{}
+		_spec = ijq.querySpec()
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*ImportJob).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &ImportJob{config: ijq.config}
+		nodes = append(nodes, node)
+		return node.assignValues(columns, values)
+	}
+	if len(ijq.modifiers) > 0 {
+		_spec.Modifiers = ijq.modifiers
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, ijq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	for i := range ijq.loadTotal {
+		if err := ijq.loadTotal[i](ctx, nodes); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (ijq *ImportJobQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := ijq.querySpec()
+	if len(ijq.modifiers) > 0 {
+		_spec.Modifiers = ijq.modifiers
+	}
+	_spec.Node.Columns = ijq.ctx.Fields
+	if len(ijq.ctx.Fields) > 0 {
+		_spec.Unique = ijq.ctx.Unique != nil && *ijq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, ijq.driver, _spec)
+}
+
+func (ijq *ImportJobQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(importjob.Table, importjob.Columns, sqlgraph.NewFieldSpec(importjob.FieldID, field.TypeInt))
+	_spec.From = ijq.sql
+	if unique := ijq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if ijq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := ijq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, importjob.FieldID)
+		for i := range fields {
+			if fields[i] != importjob.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := ijq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := ijq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := ijq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := ijq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (ijq *ImportJobQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(ijq.driver.Dialect())
+	t1 := builder.Table(importjob.Table)
+	columns := ijq.ctx.Fields
+	if len(columns) == 0 {
+		columns = importjob.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if ijq.sql != nil {
+		selector = ijq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if ijq.ctx.Unique != nil && *ijq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, m := range ijq.modifiers {
+		m(selector)
+	}
+	for _, p := range ijq.predicates {
+		p(selector)
+	}
+	for _, p := range ijq.order {
+		p(selector)
+	}
+	if offset := ijq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := ijq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ijq *ImportJobQuery) Modify(modifiers ...func(s *sql.Selector)) *ImportJobSelect {
+	ijq.modifiers = append(ijq.modifiers, modifiers...)
+	return ijq.Select()
+}
+
+// ImportJobGroupBy is the group-by builder for ImportJob entities.
+type ImportJobGroupBy struct {
+	selector
+	build *ImportJobQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ijgb *ImportJobGroupBy) Aggregate(fns ...AggregateFunc) *ImportJobGroupBy {
+	ijgb.fns = append(ijgb.fns, fns...)
+	return ijgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ijgb *ImportJobGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ijgb.build.ctx, "GroupBy")
+	if err := ijgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*ImportJobQuery, *ImportJobGroupBy](ctx, ijgb.build, ijgb, ijgb.build.inters, v)
+}
+
+func (ijgb *ImportJobGroupBy) sqlScan(ctx context.Context, root *ImportJobQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ijgb.fns))
+	for _, fn := range ijgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ijgb.flds)+len(ijgb.fns))
+		for _, f := range *ijgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ijgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ijgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// ImportJobSelect is the builder for selecting fields of ImportJob entities.
+type ImportJobSelect struct {
+	*ImportJobQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (ijs *ImportJobSelect) Aggregate(fns ...AggregateFunc) *ImportJobSelect {
+	ijs.fns = append(ijs.fns, fns...)
+	return ijs
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ijs *ImportJobSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ijs.ctx, "Select")
+	if err := ijs.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*ImportJobQuery, *ImportJobSelect](ctx, ijs.ImportJobQuery, ijs, ijs.inters, v)
+}
+
+func (ijs *ImportJobSelect) sqlScan(ctx context.Context, root *ImportJobQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(ijs.fns))
+	for _, fn := range ijs.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*ijs.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ijs.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (ijs *ImportJobSelect) Modify(modifiers ...func(s *sql.Selector)) *ImportJobSelect {
+	ijs.modifiers = append(ijs.modifiers, modifiers...)
+	return ijs
+}
@@ -0,0 +1,748 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/tonymj76/mytheresa-test/ent/importjob"
+	"github.com/tonymj76/mytheresa-test/ent/predicate"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+// ImportJobUpdate is the builder for updating ImportJob entities.
+type ImportJobUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *ImportJobMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the ImportJobUpdate builder.
+func (iju *ImportJobUpdate) Where(ps ...predicate.ImportJob) *ImportJobUpdate {
+	iju.mutation.Where(ps...)
+	return iju
+}
+
+// SetStatus sets the "status" field.
+func (iju *ImportJobUpdate) SetStatus(i importjob.Status) *ImportJobUpdate {
+	iju.mutation.SetStatus(i)
+	return iju
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableStatus(i *importjob.Status) *ImportJobUpdate {
+	if i != nil {
+		iju.SetStatus(*i)
+	}
+	return iju
+}
+
+// SetMode sets the "mode" field.
+func (iju *ImportJobUpdate) SetMode(i importjob.Mode) *ImportJobUpdate {
+	iju.mutation.SetMode(i)
+	return iju
+}
+
+// SetNillableMode sets the "mode" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableMode(i *importjob.Mode) *ImportJobUpdate {
+	if i != nil {
+		iju.SetMode(*i)
+	}
+	return iju
+}
+
+// SetFormat sets the "format" field.
+func (iju *ImportJobUpdate) SetFormat(s string) *ImportJobUpdate {
+	iju.mutation.SetFormat(s)
+	return iju
+}
+
+// SetNillableFormat sets the "format" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableFormat(s *string) *ImportJobUpdate {
+	if s != nil {
+		iju.SetFormat(*s)
+	}
+	return iju
+}
+
+// SetTotalRows sets the "total_rows" field.
+func (iju *ImportJobUpdate) SetTotalRows(i int) *ImportJobUpdate {
+	iju.mutation.ResetTotalRows()
+	iju.mutation.SetTotalRows(i)
+	return iju
+}
+
+// SetNillableTotalRows sets the "total_rows" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableTotalRows(i *int) *ImportJobUpdate {
+	if i != nil {
+		iju.SetTotalRows(*i)
+	}
+	return iju
+}
+
+// AddTotalRows adds i to the "total_rows" field.
+func (iju *ImportJobUpdate) AddTotalRows(i int) *ImportJobUpdate {
+	iju.mutation.AddTotalRows(i)
+	return iju
+}
+
+// SetProcessedRows sets the "processed_rows" field.
+func (iju *ImportJobUpdate) SetProcessedRows(i int) *ImportJobUpdate {
+	iju.mutation.ResetProcessedRows()
+	iju.mutation.SetProcessedRows(i)
+	return iju
+}
+
+// SetNillableProcessedRows sets the "processed_rows" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableProcessedRows(i *int) *ImportJobUpdate {
+	if i != nil {
+		iju.SetProcessedRows(*i)
+	}
+	return iju
+}
+
+// AddProcessedRows adds i to the "processed_rows" field.
+func (iju *ImportJobUpdate) AddProcessedRows(i int) *ImportJobUpdate {
+	iju.mutation.AddProcessedRows(i)
+	return iju
+}
+
+// SetCreatedRows sets the "created_rows" field.
+func (iju *ImportJobUpdate) SetCreatedRows(i int) *ImportJobUpdate {
+	iju.mutation.ResetCreatedRows()
+	iju.mutation.SetCreatedRows(i)
+	return iju
+}
+
+// SetNillableCreatedRows sets the "created_rows" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableCreatedRows(i *int) *ImportJobUpdate {
+	if i != nil {
+		iju.SetCreatedRows(*i)
+	}
+	return iju
+}
+
+// AddCreatedRows adds i to the "created_rows" field.
+func (iju *ImportJobUpdate) AddCreatedRows(i int) *ImportJobUpdate {
+	iju.mutation.AddCreatedRows(i)
+	return iju
+}
+
+// SetUpdatedRows sets the "updated_rows" field.
+func (iju *ImportJobUpdate) SetUpdatedRows(i int) *ImportJobUpdate {
+	iju.mutation.ResetUpdatedRows()
+	iju.mutation.SetUpdatedRows(i)
+	return iju
+}
+
+// SetNillableUpdatedRows sets the "updated_rows" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableUpdatedRows(i *int) *ImportJobUpdate {
+	if i != nil {
+		iju.SetUpdatedRows(*i)
+	}
+	return iju
+}
+
+// AddUpdatedRows adds i to the "updated_rows" field.
+func (iju *ImportJobUpdate) AddUpdatedRows(i int) *ImportJobUpdate {
+	iju.mutation.AddUpdatedRows(i)
+	return iju
+}
+
+// SetFailedRows sets the "failed_rows" field.
+func (iju *ImportJobUpdate) SetFailedRows(i int) *ImportJobUpdate {
+	iju.mutation.ResetFailedRows()
+	iju.mutation.SetFailedRows(i)
+	return iju
+}
+
+// SetNillableFailedRows sets the "failed_rows" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableFailedRows(i *int) *ImportJobUpdate {
+	if i != nil {
+		iju.SetFailedRows(*i)
+	}
+	return iju
+}
+
+// AddFailedRows adds i to the "failed_rows" field.
+func (iju *ImportJobUpdate) AddFailedRows(i int) *ImportJobUpdate {
+	iju.mutation.AddFailedRows(i)
+	return iju
+}
+
+// SetErrors sets the "errors" field.
+func (iju *ImportJobUpdate) SetErrors(me []models.RowError) *ImportJobUpdate {
+	iju.mutation.SetErrors(me)
+	return iju
+}
+
+// AppendErrors appends me to the "errors" field.
+func (iju *ImportJobUpdate) AppendErrors(me []models.RowError) *ImportJobUpdate {
+	iju.mutation.AppendErrors(me)
+	return iju
+}
+
+// ClearErrors clears the value of the "errors" field.
+func (iju *ImportJobUpdate) ClearErrors() *ImportJobUpdate {
+	iju.mutation.ClearErrors()
+	return iju
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (iju *ImportJobUpdate) SetUpdatedAt(t time.Time) *ImportJobUpdate {
+	iju.mutation.SetUpdatedAt(t)
+	return iju
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (iju *ImportJobUpdate) SetFinishedAt(t time.Time) *ImportJobUpdate {
+	iju.mutation.SetFinishedAt(t)
+	return iju
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (iju *ImportJobUpdate) SetNillableFinishedAt(t *time.Time) *ImportJobUpdate {
+	if t != nil {
+		iju.SetFinishedAt(*t)
+	}
+	return iju
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (iju *ImportJobUpdate) ClearFinishedAt() *ImportJobUpdate {
+	iju.mutation.ClearFinishedAt()
+	return iju
+}
+
+// Mutation returns the ImportJobMutation object of the builder.
+func (iju *ImportJobUpdate) Mutation() *ImportJobMutation {
+	return iju.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (iju *ImportJobUpdate) Save(ctx context.Context) (int, error) {
+	iju.defaults()
+	return withHooks(ctx, iju.sqlSave, iju.mutation, iju.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iju *ImportJobUpdate) SaveX(ctx context.Context) int {
+	affected, err := iju.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (iju *ImportJobUpdate) Exec(ctx context.Context) error {
+	_, err := iju.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iju *ImportJobUpdate) ExecX(ctx context.Context) {
+	if err := iju.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (iju *ImportJobUpdate) defaults() {
+	if _, ok := iju.mutation.UpdatedAt(); !ok {
+		v := importjob.UpdateDefaultUpdatedAt()
+		iju.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iju *ImportJobUpdate) check() error {
+	if v, ok := iju.mutation.Status(); ok {
+		if err := importjob.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "ImportJob.status": %w`, err)}
+		}
+	}
+	if v, ok := iju.mutation.Mode(); ok {
+		if err := importjob.ModeValidator(v); err != nil {
+			return &ValidationError{Name: "mode", err: fmt.Errorf(`ent: validator failed for field "ImportJob.mode": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (iju *ImportJobUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ImportJobUpdate {
+	iju.modifiers = append(iju.modifiers, modifiers...)
+	return iju
+}
+
+func (iju *ImportJobUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := iju.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(importjob.Table, importjob.Columns, sqlgraph.NewFieldSpec(importjob.FieldID, field.TypeInt))
+	if ps := iju.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iju.mutation.Status(); ok {
+		_spec.SetField(importjob.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := iju.mutation.Mode(); ok {
+		_spec.SetField(importjob.FieldMode, field.TypeEnum, value)
+	}
+	if value, ok := iju.mutation.Format(); ok {
+		_spec.SetField(importjob.FieldFormat, field.TypeString, value)
+	}
+	if value, ok := iju.mutation.TotalRows(); ok {
+		_spec.SetField(importjob.FieldTotalRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.AddedTotalRows(); ok {
+		_spec.AddField(importjob.FieldTotalRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.ProcessedRows(); ok {
+		_spec.SetField(importjob.FieldProcessedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.AddedProcessedRows(); ok {
+		_spec.AddField(importjob.FieldProcessedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.CreatedRows(); ok {
+		_spec.SetField(importjob.FieldCreatedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.AddedCreatedRows(); ok {
+		_spec.AddField(importjob.FieldCreatedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.UpdatedRows(); ok {
+		_spec.SetField(importjob.FieldUpdatedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.AddedUpdatedRows(); ok {
+		_spec.AddField(importjob.FieldUpdatedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.FailedRows(); ok {
+		_spec.SetField(importjob.FieldFailedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.AddedFailedRows(); ok {
+		_spec.AddField(importjob.FieldFailedRows, field.TypeInt, value)
+	}
+	if value, ok := iju.mutation.Errors(); ok {
+		_spec.SetField(importjob.FieldErrors, field.TypeJSON, value)
+	}
+	if value, ok := iju.mutation.AppendedErrors(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, importjob.FieldErrors, value)
+		})
+	}
+	if iju.mutation.ErrorsCleared() {
+		_spec.ClearField(importjob.FieldErrors, field.TypeJSON)
+	}
+	if value, ok := iju.mutation.UpdatedAt(); ok {
+		_spec.SetField(importjob.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := iju.mutation.FinishedAt(); ok {
+		_spec.SetField(importjob.FieldFinishedAt, field.TypeTime, value)
+	}
+	if iju.mutation.FinishedAtCleared() {
+		_spec.ClearField(importjob.FieldFinishedAt, field.TypeTime)
+	}
+	_spec.AddModifiers(iju.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, iju.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{importjob.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	iju.mutation.done = true
+	return n, nil
+}
+
+// ImportJobUpdateOne is the builder for updating a single ImportJob entity.
+type ImportJobUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *ImportJobMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetStatus sets the "status" field.
+func (ijuo *ImportJobUpdateOne) SetStatus(i importjob.Status) *ImportJobUpdateOne {
+	ijuo.mutation.SetStatus(i)
+	return ijuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableStatus(i *importjob.Status) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetStatus(*i)
+	}
+	return ijuo
+}
+
+// SetMode sets the "mode" field.
+func (ijuo *ImportJobUpdateOne) SetMode(i importjob.Mode) *ImportJobUpdateOne {
+	ijuo.mutation.SetMode(i)
+	return ijuo
+}
+
+// SetNillableMode sets the "mode" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableMode(i *importjob.Mode) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetMode(*i)
+	}
+	return ijuo
+}
+
+// SetFormat sets the "format" field.
+func (ijuo *ImportJobUpdateOne) SetFormat(s string) *ImportJobUpdateOne {
+	ijuo.mutation.SetFormat(s)
+	return ijuo
+}
+
+// SetNillableFormat sets the "format" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableFormat(s *string) *ImportJobUpdateOne {
+	if s != nil {
+		ijuo.SetFormat(*s)
+	}
+	return ijuo
+}
+
+// SetTotalRows sets the "total_rows" field.
+func (ijuo *ImportJobUpdateOne) SetTotalRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.ResetTotalRows()
+	ijuo.mutation.SetTotalRows(i)
+	return ijuo
+}
+
+// SetNillableTotalRows sets the "total_rows" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableTotalRows(i *int) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetTotalRows(*i)
+	}
+	return ijuo
+}
+
+// AddTotalRows adds i to the "total_rows" field.
+func (ijuo *ImportJobUpdateOne) AddTotalRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.AddTotalRows(i)
+	return ijuo
+}
+
+// SetProcessedRows sets the "processed_rows" field.
+func (ijuo *ImportJobUpdateOne) SetProcessedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.ResetProcessedRows()
+	ijuo.mutation.SetProcessedRows(i)
+	return ijuo
+}
+
+// SetNillableProcessedRows sets the "processed_rows" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableProcessedRows(i *int) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetProcessedRows(*i)
+	}
+	return ijuo
+}
+
+// AddProcessedRows adds i to the "processed_rows" field.
+func (ijuo *ImportJobUpdateOne) AddProcessedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.AddProcessedRows(i)
+	return ijuo
+}
+
+// SetCreatedRows sets the "created_rows" field.
+func (ijuo *ImportJobUpdateOne) SetCreatedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.ResetCreatedRows()
+	ijuo.mutation.SetCreatedRows(i)
+	return ijuo
+}
+
+// SetNillableCreatedRows sets the "created_rows" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableCreatedRows(i *int) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetCreatedRows(*i)
+	}
+	return ijuo
+}
+
+// AddCreatedRows adds i to the "created_rows" field.
+func (ijuo *ImportJobUpdateOne) AddCreatedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.AddCreatedRows(i)
+	return ijuo
+}
+
+// SetUpdatedRows sets the "updated_rows" field.
+func (ijuo *ImportJobUpdateOne) SetUpdatedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.ResetUpdatedRows()
+	ijuo.mutation.SetUpdatedRows(i)
+	return ijuo
+}
+
+// SetNillableUpdatedRows sets the "updated_rows" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableUpdatedRows(i *int) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetUpdatedRows(*i)
+	}
+	return ijuo
+}
+
+// AddUpdatedRows adds i to the "updated_rows" field.
+func (ijuo *ImportJobUpdateOne) AddUpdatedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.AddUpdatedRows(i)
+	return ijuo
+}
+
+// SetFailedRows sets the "failed_rows" field.
+func (ijuo *ImportJobUpdateOne) SetFailedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.ResetFailedRows()
+	ijuo.mutation.SetFailedRows(i)
+	return ijuo
+}
+
+// SetNillableFailedRows sets the "failed_rows" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableFailedRows(i *int) *ImportJobUpdateOne {
+	if i != nil {
+		ijuo.SetFailedRows(*i)
+	}
+	return ijuo
+}
+
+// AddFailedRows adds i to the "failed_rows" field.
+func (ijuo *ImportJobUpdateOne) AddFailedRows(i int) *ImportJobUpdateOne {
+	ijuo.mutation.AddFailedRows(i)
+	return ijuo
+}
+
+// SetErrors sets the "errors" field.
+func (ijuo *ImportJobUpdateOne) SetErrors(me []models.RowError) *ImportJobUpdateOne {
+	ijuo.mutation.SetErrors(me)
+	return ijuo
+}
+
+// AppendErrors appends me to the "errors" field.
+func (ijuo *ImportJobUpdateOne) AppendErrors(me []models.RowError) *ImportJobUpdateOne {
+	ijuo.mutation.AppendErrors(me)
+	return ijuo
+}
+
+// ClearErrors clears the value of the "errors" field.
+func (ijuo *ImportJobUpdateOne) ClearErrors() *ImportJobUpdateOne {
+	ijuo.mutation.ClearErrors()
+	return ijuo
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ijuo *ImportJobUpdateOne) SetUpdatedAt(t time.Time) *ImportJobUpdateOne {
+	ijuo.mutation.SetUpdatedAt(t)
+	return ijuo
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (ijuo *ImportJobUpdateOne) SetFinishedAt(t time.Time) *ImportJobUpdateOne {
+	ijuo.mutation.SetFinishedAt(t)
+	return ijuo
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (ijuo *ImportJobUpdateOne) SetNillableFinishedAt(t *time.Time) *ImportJobUpdateOne {
+	if t != nil {
+		ijuo.SetFinishedAt(*t)
+	}
+	return ijuo
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (ijuo *ImportJobUpdateOne) ClearFinishedAt() *ImportJobUpdateOne {
+	ijuo.mutation.ClearFinishedAt()
+	return ijuo
+}
+
+// Mutation returns the ImportJobMutation object of the builder.
+func (ijuo *ImportJobUpdateOne) Mutation() *ImportJobMutation {
+	return ijuo.mutation
+}
+
+// Where appends a list predicates to the ImportJobUpdate builder.
+func (ijuo *ImportJobUpdateOne) Where(ps ...predicate.ImportJob) *ImportJobUpdateOne {
+	ijuo.mutation.Where(ps...)
+	return ijuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ijuo *ImportJobUpdateOne) Select(field string, fields ...string) *ImportJobUpdateOne {
+	ijuo.fields = append([]string{field}, fields...)
+	return ijuo
+}
+
+// Save executes the query and returns the updated ImportJob entity.
+func (ijuo *ImportJobUpdateOne) Save(ctx context.Context) (*ImportJob, error) {
+	ijuo.defaults()
+	return withHooks(ctx, ijuo.sqlSave, ijuo.mutation, ijuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ijuo *ImportJobUpdateOne) SaveX(ctx context.Context) *ImportJob {
+	node, err := ijuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ijuo *ImportJobUpdateOne) Exec(ctx context.Context) error {
+	_, err := ijuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ijuo *ImportJobUpdateOne) ExecX(ctx context.Context) {
+	if err := ijuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ijuo *ImportJobUpdateOne) defaults() {
+	if _, ok := ijuo.mutation.UpdatedAt(); !ok {
+		v := importjob.UpdateDefaultUpdatedAt()
+		ijuo.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ijuo *ImportJobUpdateOne) check() error {
+	if v, ok := ijuo.mutation.Status(); ok {
+		if err := importjob.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "ImportJob.status": %w`, err)}
+		}
+	}
+	if v, ok := ijuo.mutation.Mode(); ok {
+		if err := importjob.ModeValidator(v); err != nil {
+			return &ValidationError{Name: "mode", err: fmt.Errorf(`ent: validator failed for field "ImportJob.mode": %w`, err)}
+		}
+	}
+	return nil
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (ijuo *ImportJobUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ImportJobUpdateOne {
+	ijuo.modifiers = append(ijuo.modifiers, modifiers...)
+	return ijuo
+}
+
+func (ijuo *ImportJobUpdateOne) sqlSave(ctx context.Context) (_node *ImportJob, err error) {
+	if err := ijuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(importjob.Table, importjob.Columns, sqlgraph.NewFieldSpec(importjob.FieldID, field.TypeInt))
+	id, ok := ijuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ImportJob.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ijuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, importjob.FieldID)
+		for _, f := range fields {
+			if !importjob.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != importjob.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ijuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ijuo.mutation.Status(); ok {
+		_spec.SetField(importjob.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := ijuo.mutation.Mode(); ok {
+		_spec.SetField(importjob.FieldMode, field.TypeEnum, value)
+	}
+	if value, ok := ijuo.mutation.Format(); ok {
+		_spec.SetField(importjob.FieldFormat, field.TypeString, value)
+	}
+	if value, ok := ijuo.mutation.TotalRows(); ok {
+		_spec.SetField(importjob.FieldTotalRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.AddedTotalRows(); ok {
+		_spec.AddField(importjob.FieldTotalRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.ProcessedRows(); ok {
+		_spec.SetField(importjob.FieldProcessedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.AddedProcessedRows(); ok {
+		_spec.AddField(importjob.FieldProcessedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.CreatedRows(); ok {
+		_spec.SetField(importjob.FieldCreatedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.AddedCreatedRows(); ok {
+		_spec.AddField(importjob.FieldCreatedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.UpdatedRows(); ok {
+		_spec.SetField(importjob.FieldUpdatedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.AddedUpdatedRows(); ok {
+		_spec.AddField(importjob.FieldUpdatedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.FailedRows(); ok {
+		_spec.SetField(importjob.FieldFailedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.AddedFailedRows(); ok {
+		_spec.AddField(importjob.FieldFailedRows, field.TypeInt, value)
+	}
+	if value, ok := ijuo.mutation.Errors(); ok {
+		_spec.SetField(importjob.FieldErrors, field.TypeJSON, value)
+	}
+	if value, ok := ijuo.mutation.AppendedErrors(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, importjob.FieldErrors, value)
+		})
+	}
+	if ijuo.mutation.ErrorsCleared() {
+		_spec.ClearField(importjob.FieldErrors, field.TypeJSON)
+	}
+	if value, ok := ijuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(importjob.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := ijuo.mutation.FinishedAt(); ok {
+		_spec.SetField(importjob.FieldFinishedAt, field.TypeTime, value)
+	}
+	if ijuo.mutation.FinishedAtCleared() {
+		_spec.ClearField(importjob.FieldFinishedAt, field.TypeTime)
+	}
+	_spec.AddModifiers(ijuo.modifiers...)
+	_node = &ImportJob{config: ijuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ijuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{importjob.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ijuo.mutation.done = true
+	return _node, nil
+}
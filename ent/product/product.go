@@ -20,6 +20,8 @@ const (
 	FieldSku = "sku"
 	// FieldName holds the string denoting the name field in the database.
 	FieldName = "name"
+	// FieldNameTranslations holds the string denoting the name_translations field in the database.
+	FieldNameTranslations = "name_translations"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
@@ -43,6 +45,7 @@ var Columns = []string{
 	FieldPrice,
 	FieldSku,
 	FieldName,
+	FieldNameTranslations,
 	FieldCreatedAt,
 	FieldUpdatedAt,
 }
@@ -77,6 +80,8 @@ var (
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
 	DefaultUpdatedAt func() time.Time
+	// UpdateDefaultUpdatedAt holds the default value on update for the "updated_at" field.
+	UpdateDefaultUpdatedAt func() time.Time
 )
 
 // OrderOption defines the ordering options for the Product queries.
@@ -250,6 +250,16 @@ func NameContainsFold(v string) predicate.Product {
 	return predicate.Product(sql.FieldContainsFold(FieldName, v))
 }
 
+// NameTranslationsIsNil applies the IsNil predicate on the "name_translations" field.
+func NameTranslationsIsNil() predicate.Product {
+	return predicate.Product(sql.FieldIsNull(FieldNameTranslations))
+}
+
+// NameTranslationsNotNil applies the NotNil predicate on the "name_translations" field.
+func NameTranslationsNotNil() predicate.Product {
+	return predicate.Product(sql.FieldNotNull(FieldNameTranslations))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Product {
 	return predicate.Product(sql.FieldEQ(FieldCreatedAt, v))
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/tonymj76/mytheresa-test/ent/category"
@@ -19,6 +20,7 @@ type ProductCreate struct {
 	config
 	mutation *ProductMutation
 	hooks    []Hook
+	conflict []sql.ConflictOption
 }
 
 // SetPrice sets the "price" field.
@@ -39,6 +41,12 @@ func (pc *ProductCreate) SetName(s string) *ProductCreate {
 	return pc
 }
 
+// SetNameTranslations sets the "name_translations" field.
+func (pc *ProductCreate) SetNameTranslations(m map[string]string) *ProductCreate {
+	pc.mutation.SetNameTranslations(m)
+	return pc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (pc *ProductCreate) SetCreatedAt(t time.Time) *ProductCreate {
 	pc.mutation.SetCreatedAt(t)
@@ -184,6 +192,7 @@ func (pc *ProductCreate) createSpec() (*Product, *sqlgraph.CreateSpec) {
 		_node = &Product{config: pc.config}
 		_spec = sqlgraph.NewCreateSpec(product.Table, sqlgraph.NewFieldSpec(product.FieldID, field.TypeInt))
 	)
+	_spec.OnConflict = pc.conflict
 	if value, ok := pc.mutation.Price(); ok {
 		_spec.SetField(product.FieldPrice, field.TypeInt, value)
 		_node.Price = value
@@ -196,6 +205,10 @@ func (pc *ProductCreate) createSpec() (*Product, *sqlgraph.CreateSpec) {
 		_spec.SetField(product.FieldName, field.TypeString, value)
 		_node.Name = value
 	}
+	if value, ok := pc.mutation.NameTranslations(); ok {
+		_spec.SetField(product.FieldNameTranslations, field.TypeJSON, value)
+		_node.NameTranslations = value
+	}
 	if value, ok := pc.mutation.CreatedAt(); ok {
 		_spec.SetField(product.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -224,11 +237,316 @@ func (pc *ProductCreate) createSpec() (*Product, *sqlgraph.CreateSpec) {
 	return _node, _spec
 }
 
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Product.Create().
+//		SetPrice(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ProductUpsert) {
+//			SetPrice(v+v).
+//		}).
+//		Exec(ctx)
+func (pc *ProductCreate) OnConflict(opts ...sql.ConflictOption) *ProductUpsertOne {
+	pc.conflict = opts
+	return &ProductUpsertOne{
+		create: pc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (pc *ProductCreate) OnConflictColumns(columns ...string) *ProductUpsertOne {
+	pc.conflict = append(pc.conflict, sql.ConflictColumns(columns...))
+	return &ProductUpsertOne{
+		create: pc,
+	}
+}
+
+type (
+	// ProductUpsertOne is the builder for "upsert"-ing
+	//  one Product node.
+	ProductUpsertOne struct {
+		create *ProductCreate
+	}
+
+	// ProductUpsert is the "OnConflict" setter.
+	ProductUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetPrice sets the "price" field.
+func (u *ProductUpsert) SetPrice(v int) *ProductUpsert {
+	u.Set(product.FieldPrice, v)
+	return u
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *ProductUpsert) UpdatePrice() *ProductUpsert {
+	u.SetExcluded(product.FieldPrice)
+	return u
+}
+
+// AddPrice adds v to the "price" field.
+func (u *ProductUpsert) AddPrice(v int) *ProductUpsert {
+	u.Add(product.FieldPrice, v)
+	return u
+}
+
+// SetSku sets the "sku" field.
+func (u *ProductUpsert) SetSku(v string) *ProductUpsert {
+	u.Set(product.FieldSku, v)
+	return u
+}
+
+// UpdateSku sets the "sku" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateSku() *ProductUpsert {
+	u.SetExcluded(product.FieldSku)
+	return u
+}
+
+// SetName sets the "name" field.
+func (u *ProductUpsert) SetName(v string) *ProductUpsert {
+	u.Set(product.FieldName, v)
+	return u
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateName() *ProductUpsert {
+	u.SetExcluded(product.FieldName)
+	return u
+}
+
+// SetNameTranslations sets the "name_translations" field.
+func (u *ProductUpsert) SetNameTranslations(v map[string]string) *ProductUpsert {
+	u.Set(product.FieldNameTranslations, v)
+	return u
+}
+
+// UpdateNameTranslations sets the "name_translations" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateNameTranslations() *ProductUpsert {
+	u.SetExcluded(product.FieldNameTranslations)
+	return u
+}
+
+// ClearNameTranslations clears the value of the "name_translations" field.
+func (u *ProductUpsert) ClearNameTranslations() *ProductUpsert {
+	u.SetNull(product.FieldNameTranslations)
+	return u
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (u *ProductUpsert) SetCreatedAt(v time.Time) *ProductUpsert {
+	u.Set(product.FieldCreatedAt, v)
+	return u
+}
+
+// UpdateCreatedAt sets the "created_at" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateCreatedAt() *ProductUpsert {
+	u.SetExcluded(product.FieldCreatedAt)
+	return u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ProductUpsert) SetUpdatedAt(v time.Time) *ProductUpsert {
+	u.Set(product.FieldUpdatedAt, v)
+	return u
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ProductUpsert) UpdateUpdatedAt() *ProductUpsert {
+	u.SetExcluded(product.FieldUpdatedAt)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ProductUpsertOne) UpdateNewValues() *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *ProductUpsertOne) Ignore() *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ProductUpsertOne) DoNothing() *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ProductCreate.OnConflict
+// documentation for more info.
+func (u *ProductUpsertOne) Update(set func(*ProductUpsert)) *ProductUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ProductUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetPrice sets the "price" field.
+func (u *ProductUpsertOne) SetPrice(v int) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetPrice(v)
+	})
+}
+
+// AddPrice adds v to the "price" field.
+func (u *ProductUpsertOne) AddPrice(v int) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.AddPrice(v)
+	})
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdatePrice() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdatePrice()
+	})
+}
+
+// SetSku sets the "sku" field.
+func (u *ProductUpsertOne) SetSku(v string) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetSku(v)
+	})
+}
+
+// UpdateSku sets the "sku" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateSku() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateSku()
+	})
+}
+
+// SetName sets the "name" field.
+func (u *ProductUpsertOne) SetName(v string) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetName(v)
+	})
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateName() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateName()
+	})
+}
+
+// SetNameTranslations sets the "name_translations" field.
+func (u *ProductUpsertOne) SetNameTranslations(v map[string]string) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetNameTranslations(v)
+	})
+}
+
+// UpdateNameTranslations sets the "name_translations" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateNameTranslations() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateNameTranslations()
+	})
+}
+
+// ClearNameTranslations clears the value of the "name_translations" field.
+func (u *ProductUpsertOne) ClearNameTranslations() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.ClearNameTranslations()
+	})
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (u *ProductUpsertOne) SetCreatedAt(v time.Time) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetCreatedAt(v)
+	})
+}
+
+// UpdateCreatedAt sets the "created_at" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateCreatedAt() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateCreatedAt()
+	})
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ProductUpsertOne) SetUpdatedAt(v time.Time) *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetUpdatedAt(v)
+	})
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ProductUpsertOne) UpdateUpdatedAt() *ProductUpsertOne {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateUpdatedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *ProductUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for ProductCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ProductUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *ProductUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *ProductUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
 // ProductCreateBulk is the builder for creating many Product entities in bulk.
 type ProductCreateBulk struct {
 	config
 	err      error
 	builders []*ProductCreate
+	conflict []sql.ConflictOption
 }
 
 // Save creates the Product entities in the database.
@@ -258,6 +576,7 @@ func (pcb *ProductCreateBulk) Save(ctx context.Context) ([]*Product, error) {
 					_, err = mutators[i+1].Mutate(root, pcb.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = pcb.conflict
 					// Invoke the actual operation on the latest mutation in the chain.
 					if err = sqlgraph.BatchCreate(ctx, pcb.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
@@ -311,3 +630,205 @@ func (pcb *ProductCreateBulk) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Product.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ProductUpsert) {
+//			SetPrice(v+v).
+//		}).
+//		Exec(ctx)
+func (pcb *ProductCreateBulk) OnConflict(opts ...sql.ConflictOption) *ProductUpsertBulk {
+	pcb.conflict = opts
+	return &ProductUpsertBulk{
+		create: pcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (pcb *ProductCreateBulk) OnConflictColumns(columns ...string) *ProductUpsertBulk {
+	pcb.conflict = append(pcb.conflict, sql.ConflictColumns(columns...))
+	return &ProductUpsertBulk{
+		create: pcb,
+	}
+}
+
+// ProductUpsertBulk is the builder for "upsert"-ing
+// a bulk of Product nodes.
+type ProductUpsertBulk struct {
+	create *ProductCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ProductUpsertBulk) UpdateNewValues() *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Product.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *ProductUpsertBulk) Ignore() *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ProductUpsertBulk) DoNothing() *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ProductCreateBulk.OnConflict
+// documentation for more info.
+func (u *ProductUpsertBulk) Update(set func(*ProductUpsert)) *ProductUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ProductUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetPrice sets the "price" field.
+func (u *ProductUpsertBulk) SetPrice(v int) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetPrice(v)
+	})
+}
+
+// AddPrice adds v to the "price" field.
+func (u *ProductUpsertBulk) AddPrice(v int) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.AddPrice(v)
+	})
+}
+
+// UpdatePrice sets the "price" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdatePrice() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdatePrice()
+	})
+}
+
+// SetSku sets the "sku" field.
+func (u *ProductUpsertBulk) SetSku(v string) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetSku(v)
+	})
+}
+
+// UpdateSku sets the "sku" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateSku() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateSku()
+	})
+}
+
+// SetName sets the "name" field.
+func (u *ProductUpsertBulk) SetName(v string) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetName(v)
+	})
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateName() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateName()
+	})
+}
+
+// SetNameTranslations sets the "name_translations" field.
+func (u *ProductUpsertBulk) SetNameTranslations(v map[string]string) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetNameTranslations(v)
+	})
+}
+
+// UpdateNameTranslations sets the "name_translations" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateNameTranslations() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateNameTranslations()
+	})
+}
+
+// ClearNameTranslations clears the value of the "name_translations" field.
+func (u *ProductUpsertBulk) ClearNameTranslations() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.ClearNameTranslations()
+	})
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (u *ProductUpsertBulk) SetCreatedAt(v time.Time) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetCreatedAt(v)
+	})
+}
+
+// UpdateCreatedAt sets the "created_at" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateCreatedAt() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateCreatedAt()
+	})
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ProductUpsertBulk) SetUpdatedAt(v time.Time) *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.SetUpdatedAt(v)
+	})
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ProductUpsertBulk) UpdateUpdatedAt() *ProductUpsertBulk {
+	return u.Update(func(s *ProductUpsert) {
+		s.UpdateUpdatedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *ProductUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("ent: OnConflict was set for builder %d. Set it on the ProductCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for ProductCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ProductUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
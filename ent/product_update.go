@@ -19,8 +19,9 @@ import (
 // ProductUpdate is the builder for updating Product entities.
 type ProductUpdate struct {
 	config
-	hooks    []Hook
-	mutation *ProductMutation
+	hooks     []Hook
+	mutation  *ProductMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // Where appends a list predicates to the ProductUpdate builder.
@@ -78,6 +79,18 @@ func (pu *ProductUpdate) SetNillableName(s *string) *ProductUpdate {
 	return pu
 }
 
+// SetNameTranslations sets the "name_translations" field.
+func (pu *ProductUpdate) SetNameTranslations(m map[string]string) *ProductUpdate {
+	pu.mutation.SetNameTranslations(m)
+	return pu
+}
+
+// ClearNameTranslations clears the value of the "name_translations" field.
+func (pu *ProductUpdate) ClearNameTranslations() *ProductUpdate {
+	pu.mutation.ClearNameTranslations()
+	return pu
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (pu *ProductUpdate) SetCreatedAt(t time.Time) *ProductUpdate {
 	pu.mutation.SetCreatedAt(t)
@@ -98,14 +111,6 @@ func (pu *ProductUpdate) SetUpdatedAt(t time.Time) *ProductUpdate {
 	return pu
 }
 
-// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
-func (pu *ProductUpdate) SetNillableUpdatedAt(t *time.Time) *ProductUpdate {
-	if t != nil {
-		pu.SetUpdatedAt(*t)
-	}
-	return pu
-}
-
 // SetCategoryID sets the "category" edge to the Category entity by ID.
 func (pu *ProductUpdate) SetCategoryID(id int) *ProductUpdate {
 	pu.mutation.SetCategoryID(id)
@@ -138,6 +143,7 @@ func (pu *ProductUpdate) ClearCategory() *ProductUpdate {
 
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (pu *ProductUpdate) Save(ctx context.Context) (int, error) {
+	pu.defaults()
 	return withHooks(ctx, pu.sqlSave, pu.mutation, pu.hooks)
 }
 
@@ -163,6 +169,14 @@ func (pu *ProductUpdate) ExecX(ctx context.Context) {
 	}
 }
 
+// defaults sets the default values of the builder before save.
+func (pu *ProductUpdate) defaults() {
+	if _, ok := pu.mutation.UpdatedAt(); !ok {
+		v := product.UpdateDefaultUpdatedAt()
+		pu.mutation.SetUpdatedAt(v)
+	}
+}
+
 // check runs all checks and user-defined validators on the builder.
 func (pu *ProductUpdate) check() error {
 	if v, ok := pu.mutation.Sku(); ok {
@@ -178,6 +192,12 @@ func (pu *ProductUpdate) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (pu *ProductUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ProductUpdate {
+	pu.modifiers = append(pu.modifiers, modifiers...)
+	return pu
+}
+
 func (pu *ProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if err := pu.check(); err != nil {
 		return n, err
@@ -202,6 +222,12 @@ func (pu *ProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := pu.mutation.Name(); ok {
 		_spec.SetField(product.FieldName, field.TypeString, value)
 	}
+	if value, ok := pu.mutation.NameTranslations(); ok {
+		_spec.SetField(product.FieldNameTranslations, field.TypeJSON, value)
+	}
+	if pu.mutation.NameTranslationsCleared() {
+		_spec.ClearField(product.FieldNameTranslations, field.TypeJSON)
+	}
 	if value, ok := pu.mutation.CreatedAt(); ok {
 		_spec.SetField(product.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -237,6 +263,7 @@ func (pu *ProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(pu.modifiers...)
 	if n, err = sqlgraph.UpdateNodes(ctx, pu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{product.Label}
@@ -252,9 +279,10 @@ func (pu *ProductUpdate) sqlSave(ctx context.Context) (n int, err error) {
 // ProductUpdateOne is the builder for updating a single Product entity.
 type ProductUpdateOne struct {
 	config
-	fields   []string
-	hooks    []Hook
-	mutation *ProductMutation
+	fields    []string
+	hooks     []Hook
+	mutation  *ProductMutation
+	modifiers []func(*sql.UpdateBuilder)
 }
 
 // SetPrice sets the "price" field.
@@ -306,6 +334,18 @@ func (puo *ProductUpdateOne) SetNillableName(s *string) *ProductUpdateOne {
 	return puo
 }
 
+// SetNameTranslations sets the "name_translations" field.
+func (puo *ProductUpdateOne) SetNameTranslations(m map[string]string) *ProductUpdateOne {
+	puo.mutation.SetNameTranslations(m)
+	return puo
+}
+
+// ClearNameTranslations clears the value of the "name_translations" field.
+func (puo *ProductUpdateOne) ClearNameTranslations() *ProductUpdateOne {
+	puo.mutation.ClearNameTranslations()
+	return puo
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (puo *ProductUpdateOne) SetCreatedAt(t time.Time) *ProductUpdateOne {
 	puo.mutation.SetCreatedAt(t)
@@ -326,14 +366,6 @@ func (puo *ProductUpdateOne) SetUpdatedAt(t time.Time) *ProductUpdateOne {
 	return puo
 }
 
-// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
-func (puo *ProductUpdateOne) SetNillableUpdatedAt(t *time.Time) *ProductUpdateOne {
-	if t != nil {
-		puo.SetUpdatedAt(*t)
-	}
-	return puo
-}
-
 // SetCategoryID sets the "category" edge to the Category entity by ID.
 func (puo *ProductUpdateOne) SetCategoryID(id int) *ProductUpdateOne {
 	puo.mutation.SetCategoryID(id)
@@ -379,6 +411,7 @@ func (puo *ProductUpdateOne) Select(field string, fields ...string) *ProductUpda
 
 // Save executes the query and returns the updated Product entity.
 func (puo *ProductUpdateOne) Save(ctx context.Context) (*Product, error) {
+	puo.defaults()
 	return withHooks(ctx, puo.sqlSave, puo.mutation, puo.hooks)
 }
 
@@ -404,6 +437,14 @@ func (puo *ProductUpdateOne) ExecX(ctx context.Context) {
 	}
 }
 
+// defaults sets the default values of the builder before save.
+func (puo *ProductUpdateOne) defaults() {
+	if _, ok := puo.mutation.UpdatedAt(); !ok {
+		v := product.UpdateDefaultUpdatedAt()
+		puo.mutation.SetUpdatedAt(v)
+	}
+}
+
 // check runs all checks and user-defined validators on the builder.
 func (puo *ProductUpdateOne) check() error {
 	if v, ok := puo.mutation.Sku(); ok {
@@ -419,6 +460,12 @@ func (puo *ProductUpdateOne) check() error {
 	return nil
 }
 
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (puo *ProductUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ProductUpdateOne {
+	puo.modifiers = append(puo.modifiers, modifiers...)
+	return puo
+}
+
 func (puo *ProductUpdateOne) sqlSave(ctx context.Context) (_node *Product, err error) {
 	if err := puo.check(); err != nil {
 		return _node, err
@@ -460,6 +507,12 @@ func (puo *ProductUpdateOne) sqlSave(ctx context.Context) (_node *Product, err e
 	if value, ok := puo.mutation.Name(); ok {
 		_spec.SetField(product.FieldName, field.TypeString, value)
 	}
+	if value, ok := puo.mutation.NameTranslations(); ok {
+		_spec.SetField(product.FieldNameTranslations, field.TypeJSON, value)
+	}
+	if puo.mutation.NameTranslationsCleared() {
+		_spec.ClearField(product.FieldNameTranslations, field.TypeJSON)
+	}
 	if value, ok := puo.mutation.CreatedAt(); ok {
 		_spec.SetField(product.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -495,6 +548,7 @@ func (puo *ProductUpdateOne) sqlSave(ctx context.Context) (_node *Product, err e
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	_spec.AddModifiers(puo.modifiers...)
 	_node = &Product{config: puo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
@@ -16,17 +18,23 @@ type Category struct {
 // Fields of the Category.
 func (Category) Fields() []ent.Field {
 	return []ent.Field{
-		field.String("name").NotEmpty().Unique(),
-		field.String("description").Nillable(),
+		field.String("name").NotEmpty().Unique().
+			Annotations(entgql.OrderField("NAME")),
+		// graph/product.graphql declares the description so it resolves to the translation of the request locale
+		field.String("description").Nillable().
+			Annotations(entgql.Skip(entgql.SkipType)),
+		field.JSON("description_translations", map[string]string{}).Optional().
+			Annotations(entgql.Skip()),
 		field.Time("created_at").Default(time.Now),
-		field.Time("updated_at").Default(time.Now),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
 	}
 }
 
 // Edges of the Category.
 func (Category) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("products", Product.Type),
+		edge.To("products", Product.Type).
+			Annotations(entgql.RelayConnection()),
 	}
 }
 
@@ -35,3 +43,11 @@ func (Category) Indexes() []ent.Index {
 		index.Fields("name"),
 	}
 }
+
+// Annotations of the Category.
+func (Category) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+	}
+}
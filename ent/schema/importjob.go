@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"github.com/tonymj76/mytheresa-test/models"
+	"time"
+)
+
+// ImportJob holds the schema definition for the ImportJob entity.
+type ImportJob struct {
+	ent.Schema
+}
+
+// Fields of the ImportJob.
+func (ImportJob) Fields() []ent.Field {
+	return []ent.Field{
+		field.Enum("status").Values("pending", "running", "succeeded", "failed").Default("pending"),
+		field.Enum("mode").Values("atomic", "best_effort"),
+		field.String("format"),
+		field.Int("total_rows").Default(0),
+		field.Int("processed_rows").Default(0),
+		field.Int("created_rows").Default(0),
+		field.Int("updated_rows").Default(0),
+		field.Int("failed_rows").Default(0),
+		field.JSON("errors", []models.RowError{}).Optional(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+		field.Time("finished_at").Optional().Nillable(),
+	}
+}
+
+// Annotations of the ImportJob, import jobs are only exposed over REST.
+func (ImportJob) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
@@ -16,11 +18,19 @@ type Product struct {
 // Fields of the Product.
 func (Product) Fields() []ent.Field {
 	return []ent.Field{
-		field.Int("price"),
-		field.String("sku").NotEmpty().Unique(),
-		field.String("name").NotEmpty(),
-		field.Time("created_at").Default(time.Now),
-		field.Time("updated_at").Default(time.Now),
+		field.Int("price").
+			Annotations(entgql.OrderField("PRICE")),
+		field.String("sku").NotEmpty().Unique().
+			Annotations(entgql.OrderField("SKU")),
+		// graph/product.graphql declares the name so it resolves to the translation of the request locale
+		field.String("name").NotEmpty().
+			Annotations(entgql.OrderField("NAME"), entgql.Skip(entgql.SkipType)),
+		field.JSON("name_translations", map[string]string{}).Optional().
+			Annotations(entgql.Skip()),
+		field.Time("created_at").Default(time.Now).
+			Annotations(entgql.OrderField("CREATED_AT")),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now).
+			Annotations(entgql.OrderField("UPDATED_AT")),
 	}
 }
 
@@ -38,3 +48,11 @@ func (Product) Indexes() []ent.Index {
 		index.Fields("sku"),
 	}
 }
+
+// Annotations of the Product, the products query is declared in graph/product.graphql
+// so it takes the same filters as the REST listing.
+func (Product) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.RelayConnection(),
+	}
+}
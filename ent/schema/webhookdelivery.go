@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/tonymj76/mytheresa-test/models"
+	"time"
+)
+
+// WebhookDelivery holds the schema definition for the WebhookDelivery entity, an event sent or to be sent to a
+// webhook. The deliveries that ran out of attempts are dead and make up the dead-letter list.
+type WebhookDelivery struct {
+	ent.Schema
+}
+
+// Fields of the WebhookDelivery.
+func (WebhookDelivery) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("webhook_id"),
+		field.String("event_id").NotEmpty(),
+		field.String("event").NotEmpty(),
+		// payload is the body of every attempt, only the timestamp and the signature change between them
+		field.Bytes("payload"),
+		field.Enum("status").Values("pending", "succeeded", "dead").Default("pending"),
+		field.JSON("attempts", []models.DeliveryAttempt{}).Optional(),
+		// attempts_left counts down the failed attempts, the delivery dies at zero and a redelivery resets it
+		field.Int("attempts_left").NonNegative(),
+		// next_attempt_at is when the pending delivery is due, it is cleared once it succeeded or died
+		field.Time("next_attempt_at").Optional().Nillable(),
+		field.Time("delivered_at").Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the WebhookDelivery.
+func (WebhookDelivery) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("webhook", Webhook.Type).
+			Ref("deliveries").
+			Field("webhook_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (WebhookDelivery) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "next_attempt_at"),
+	}
+}
+
+// Annotations of the WebhookDelivery, deliveries are only exposed over REST.
+func (WebhookDelivery) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.Skip(entgql.SkipAll),
+	}
+}
@@ -0,0 +1,282 @@
+package feed
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	FormatXML = "xml"
+	FormatTSV = "tsv"
+
+	// pageSize is the number of products fetched at once while rendering the feed
+	pageSize = 500
+
+	// limits of the Google Merchant Center product data specification
+	maxIDLength          = 50
+	maxTitleLength       = 150
+	maxProductTypeLength = 750
+
+	// effectiveDateLayout is the ISO 8601 layout the spec expects for sale_price_effective_date
+	effectiveDateLayout = "2006-01-02T15:04Z0700"
+)
+
+// columns is the attribute order of the TSV feed, the header row uses the attribute names of the spec
+var columns = []string{"id", "title", "price", "sale_price", "sale_price_effective_date", "product_type"}
+
+type (
+	// Item is a single product of the feed, the xml names use the g: namespace of the spec
+	Item struct {
+		XMLName                xml.Name `xml:"item"`
+		ID                     string   `xml:"g:id"`
+		Title                  string   `xml:"g:title"`
+		Price                  string   `xml:"g:price"`
+		SalePrice              string   `xml:"g:sale_price,omitempty"`
+		SalePriceEffectiveDate string   `xml:"g:sale_price_effective_date,omitempty"`
+		ProductType            string   `xml:"g:product_type"`
+
+		// the prices in cents, kept so Validate doesn't have to parse the formatted values back
+		price     int
+		salePrice int
+	}
+
+	// Channel describes the store the feed belongs to
+	Channel struct {
+		Title       string
+		Link        string
+		Description string
+	}
+
+	// SkippedItem is a product left out of the feed because it breaks the spec
+	SkippedItem struct {
+		ID     string                  `json:"id"`
+		Errors models.ValidationErrors `json:"errors"`
+	}
+
+	// Report summarizes a rendered feed
+	Report struct {
+		Items   int           `json:"items"`
+		Skipped []SkippedItem `json:"skipped"`
+	}
+
+	// Writer writes the items in one of the feed formats, Close writes whatever closes the document. Nothing is
+	// written before the first item so a feed failing to load its first page can still be reported
+	Writer interface {
+		WriteItem(Item) error
+		Close() error
+	}
+
+	// ProductPages hands every priced product to fn one page at a time, e.g. services.RestService.EachProductPage
+	ProductPages func(ctx context.Context, pageSize int, fn func(models.Products) error) error
+)
+
+// formatPrice renders an amount in cents the way the spec expects it, e.g. "890.00 EUR"
+func formatPrice(cents int, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", cents/100, cents%100, currency)
+}
+
+// NewItem maps a priced product to a feed item, the sale attributes are only set when a discount applies
+func NewItem(pd models.Product) Item {
+	item := Item{
+		ID:          pd.SKU,
+		Title:       pd.Name,
+		Price:       formatPrice(pd.Price.Original, pd.Price.Currency),
+		ProductType: pd.Category,
+		price:       pd.Price.Original,
+	}
+	if pd.Price.Final < pd.Price.Original {
+		item.SalePrice = formatPrice(pd.Price.Final, pd.Price.Currency)
+		item.salePrice = pd.Price.Final
+		// the spec needs both ends of the range, an open-ended promotion has no effective date
+		if pd.Price.DiscountStartsAt.Valid && pd.Price.DiscountEndsAt.Valid {
+			item.SalePriceEffectiveDate = pd.Price.DiscountStartsAt.Time.Format(effectiveDateLayout) + "/" +
+				pd.Price.DiscountEndsAt.Time.Format(effectiveDateLayout)
+		}
+	}
+	return item
+}
+
+// Validate checks the item against the field constraints of the spec
+func (i Item) Validate() error {
+	var errs models.ValidationErrors
+	fail := func(field, message string) {
+		errs = append(errs, models.FieldError{Field: field, Message: message})
+	}
+
+	switch {
+	case strings.TrimSpace(i.ID) == "":
+		fail("id", "is required")
+	case utf8.RuneCountInString(i.ID) > maxIDLength:
+		fail("id", fmt.Sprintf("must be at most %d characters", maxIDLength))
+	}
+	switch {
+	case strings.TrimSpace(i.Title) == "":
+		fail("title", "is required")
+	case utf8.RuneCountInString(i.Title) > maxTitleLength:
+		fail("title", fmt.Sprintf("must be at most %d characters", maxTitleLength))
+	}
+	if i.price <= 0 {
+		fail("price", "must be greater than zero")
+	}
+	if i.SalePrice != "" && (i.salePrice <= 0 || i.salePrice >= i.price) {
+		fail("sale_price", "must be greater than zero and lower than the price")
+	}
+	if utf8.RuneCountInString(i.ProductType) > maxProductTypeLength {
+		fail("product_type", fmt.Sprintf("must be at most %d characters", maxProductTypeLength))
+	}
+	if i.SalePriceEffectiveDate != "" {
+		start, end, _ := strings.Cut(i.SalePriceEffectiveDate, "/")
+		startsAt, startErr := time.Parse(effectiveDateLayout, start)
+		endsAt, endErr := time.Parse(effectiveDateLayout, end)
+		if startErr != nil || endErr != nil || !endsAt.After(startsAt) {
+			fail("sale_price_effective_date", "must be a start/end range with the end after the start")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Render streams a feed item for every product, the products that break the spec are skipped and reported
+func Render(ctx context.Context, pages ProductPages, w Writer) (*Report, error) {
+	report := &Report{Skipped: []SkippedItem{}}
+
+	err := pages(ctx, pageSize, func(products models.Products) error {
+		for _, pd := range products {
+			item := NewItem(pd)
+			if err := item.Validate(); err != nil {
+				report.Skipped = append(report.Skipped, SkippedItem{ID: item.ID, Errors: err.(models.ValidationErrors)})
+				continue
+			}
+			if err := w.WriteItem(item); err != nil {
+				return fmt.Errorf("failed to write feed item %s: %w", item.ID, err)
+			}
+			report.Items++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close the feed: %w", err)
+	}
+	return report, nil
+}
+
+// ChannelFromEnv describes the store with the FEED_TITLE, FEED_LINK and FEED_DESCRIPTION environment variables
+func ChannelFromEnv() Channel {
+	return Channel{
+		Title:       config.GetEnv("FEED_TITLE", "mytheresa products"),
+		Link:        config.GetEnv("FEED_LINK", "https://www.mytheresa.com"),
+		Description: config.GetEnv("FEED_DESCRIPTION", "Product feed for shopping engines"),
+	}
+}
+
+// NewWriter returns the writer of the given format
+func NewWriter(format string, w io.Writer, channel Channel) (Writer, error) {
+	switch format {
+	case FormatXML:
+		return NewXMLWriter(w, channel)
+	case FormatTSV:
+		return NewTSVWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown feed format %s", format)
+	}
+}
+
+// XMLWriter writes an RSS 2.0 feed
+type XMLWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+
+	// header opens the document, it is written with the first item so nothing is sent before the products load
+	header string
+}
+
+func NewXMLWriter(w io.Writer, channel Channel) (*XMLWriter, error) {
+	var header strings.Builder
+	header.WriteString(xml.Header)
+	header.WriteString(`<rss xmlns:g="http://base.google.com/ns/1.0" version="2.0"><channel>`)
+	for _, el := range []struct{ name, value string }{
+		{"title", channel.Title}, {"link", channel.Link}, {"description", channel.Description},
+	} {
+		header.WriteString("<" + el.name + ">")
+		if err := xml.EscapeText(&header, []byte(el.value)); err != nil {
+			return nil, err
+		}
+		header.WriteString("</" + el.name + ">")
+	}
+	return &XMLWriter{w: w, enc: xml.NewEncoder(w), header: header.String()}, nil
+}
+
+func (xw *XMLWriter) writeHeader() error {
+	if xw.header == "" {
+		return nil
+	}
+	_, err := io.WriteString(xw.w, xw.header)
+	xw.header = ""
+	return err
+}
+
+func (xw *XMLWriter) WriteItem(item Item) error {
+	if err := xw.writeHeader(); err != nil {
+		return err
+	}
+	return xw.enc.Encode(item)
+}
+
+func (xw *XMLWriter) Close() error {
+	if err := xw.writeHeader(); err != nil {
+		return err
+	}
+	if err := xw.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(xw.w, "</channel></rss>\n")
+	return err
+}
+
+// TSVWriter writes a tab separated feed with a header row, the csv writer holds the header row until the first items
+// fill its buffer
+type TSVWriter struct {
+	w *csv.Writer
+}
+
+func NewTSVWriter(w io.Writer) (*TSVWriter, error) {
+	tw := &TSVWriter{w: csv.NewWriter(w)}
+	tw.w.Comma = '\t'
+	if err := tw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return tw, nil
+}
+
+func (tw *TSVWriter) WriteItem(item Item) error {
+	// tabs and new lines would break the row, the spec doesn't allow them in values anyway
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	return tw.w.Write([]string{
+		clean.Replace(item.ID),
+		clean.Replace(item.Title),
+		item.Price,
+		item.SalePrice,
+		item.SalePriceEffectiveDate,
+		clean.Replace(item.ProductType),
+	})
+}
+
+func (tw *TSVWriter) Close() error {
+	tw.w.Flush()
+	return tw.w.Error()
+}
@@ -0,0 +1,71 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.43
+
+import (
+	"context"
+	"fmt"
+
+	"entgo.io/contrib/entgql"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+// ID is the resolver for the id field.
+func (r *categoryResolver) ID(ctx context.Context, obj *ent.Category) (*GlobalID, error) {
+	return &GlobalID{Type: "Category", ID: obj.ID}, nil
+}
+
+// Products is the resolver for the products field.
+func (r *categoryResolver) Products(ctx context.Context, obj *ent.Category, after *entgql.Cursor[int], first *int, before *entgql.Cursor[int], last *int, orderBy *ent.ProductOrder) (*ent.ProductConnection, error) {
+	first, err := r.pageSize(first, last)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Products(ctx, after, first, before, last, orderBy)
+}
+
+// ID is the resolver for the id field.
+func (r *productResolver) ID(ctx context.Context, obj *ent.Product) (*GlobalID, error) {
+	return &GlobalID{Type: "Product", ID: obj.ID}, nil
+}
+
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id GlobalID) (ent.Noder, error) {
+	return r.client.Noder(ctx, id.ID, ent.WithFixedNodeType(nodeTables[id.Type]))
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []*GlobalID) ([]ent.Noder, error) {
+	if len(ids) > r.maxPageSize {
+		return nil, models.ValidationErrors{{Field: "ids", Message: fmt.Sprintf("must have at most %d ids", r.maxPageSize)}}
+	}
+	return r.loadNodes(ctx, ids)
+}
+
+// Categories is the resolver for the categories field.
+func (r *queryResolver) Categories(ctx context.Context, after *entgql.Cursor[int], first *int, before *entgql.Cursor[int], last *int, orderBy *ent.CategoryOrder) (*ent.CategoryConnection, error) {
+	first, err := r.pageSize(first, last)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.Category.Query().
+		Paginate(ctx, after, first, before, last,
+			ent.WithCategoryOrder(orderBy),
+		)
+}
+
+// Category returns CategoryResolver implementation.
+func (r *Resolver) Category() CategoryResolver { return &categoryResolver{r} }
+
+// Product returns ProductResolver implementation.
+func (r *Resolver) Product() ProductResolver { return &productResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type categoryResolver struct{ *Resolver }
+type productResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
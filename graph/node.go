@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// nodeTables maps the GraphQL types that implement Node to their table
+var nodeTables = map[string]string{
+	"Category": category.Table,
+	"Product":  product.Table,
+}
+
+// GlobalID is the Relay id of a node, the base64 of its GraphQL type and its database id such as Product:12.
+// The database ids are only unique within a table so the type tells the node query where to look
+type GlobalID struct {
+	Type string
+	ID   int
+}
+
+func (id GlobalID) String() string {
+	return base64.StdEncoding.EncodeToString([]byte(id.Type + ":" + strconv.Itoa(id.ID)))
+}
+
+// MarshalGQL writes the id as an opaque string
+func (id GlobalID) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, strconv.Quote(id.String()))
+}
+
+// UnmarshalGQL reads an id written by MarshalGQL, the type must be one of the nodes
+func (id *GlobalID) UnmarshalGQL(v any) error {
+	invalid := models.ValidationErrors{{Field: "id", Message: "invalid id, use the id returned by the api"}}
+	raw, ok := v.(string)
+	if !ok {
+		return invalid
+	}
+	decoded, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return invalid
+	}
+	typ, rawID, ok := strings.Cut(string(decoded), ":")
+	if _, node := nodeTables[typ]; !ok || !node {
+		return invalid
+	}
+	if id.ID, err = strconv.Atoi(rawID); err != nil {
+		return invalid
+	}
+	id.Type = typ
+	return nil
+}
+
+// loadNodes reads the nodes with one query per type, an id that doesn't exist leaves a null node and an error at its
+// index like the node query
+func (r *Resolver) loadNodes(ctx context.Context, ids []*GlobalID) ([]ent.Noder, error) {
+	byType := make(map[string][]int)
+	for _, id := range ids {
+		byType[id.Type] = append(byType[id.Type], id.ID)
+	}
+
+	loaded := make(map[GlobalID]ent.Noder, len(ids))
+	for typ, dbIDs := range byType {
+		switch typ {
+		case "Category":
+			query, err := r.client.Category.Query().Where(category.IDIn(dbIDs...)).CollectFields(ctx, typ)
+			if err != nil {
+				return nil, err
+			}
+			categories, err := query.All(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, cd := range categories {
+				loaded[GlobalID{Type: typ, ID: cd.ID}] = cd
+			}
+		case "Product":
+			query, err := r.client.Product.Query().Where(product.IDIn(dbIDs...)).CollectFields(ctx, typ)
+			if err != nil {
+				return nil, err
+			}
+			products, err := query.All(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, pd := range products {
+				loaded[GlobalID{Type: typ, ID: pd.ID}] = pd
+			}
+		}
+	}
+
+	nodes := make([]ent.Noder, len(ids))
+	for i, id := range ids {
+		node, ok := loaded[*id]
+		if !ok {
+			graphql.AddError(graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i)),
+				models.NewError(models.ErrorKindNotFound, "not_found", fmt.Sprintf("no node with the id %s", id)))
+			continue
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
@@ -0,0 +1,83 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.43
+
+import (
+	"context"
+
+	"entgo.io/contrib/entgql"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/services"
+)
+
+// Description is the resolver for the description field.
+func (r *categoryResolver) Description(ctx context.Context, obj *ent.Category) (string, error) {
+	var description string
+	if obj.Description != nil {
+		description = *obj.Description
+	}
+	return models.Translate(description, obj.DescriptionTranslations, config.LocaleFromContext(ctx)), nil
+}
+
+// Name is the resolver for the name field.
+func (r *productResolver) Name(ctx context.Context, obj *ent.Product) (string, error) {
+	return models.Translate(obj.Name, obj.NameTranslations, config.LocaleFromContext(ctx)), nil
+}
+
+// FinalPrice is the resolver for the finalPrice field.
+func (r *productResolver) FinalPrice(ctx context.Context, obj *ent.Product) (int, error) {
+	price, err := productPrice(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+	return price.Final, nil
+}
+
+// Discount is the resolver for the discount field.
+func (r *productResolver) Discount(ctx context.Context, obj *ent.Product) (*string, error) {
+	price, err := productPrice(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return price.DiscountPercentage.Ptr(), nil
+}
+
+// Currency is the resolver for the currency field.
+func (r *productResolver) Currency(ctx context.Context, obj *ent.Product) (string, error) {
+	return services.CURRENCY, nil
+}
+
+// Formatted is the resolver for the formatted field.
+func (r *productResolver) Formatted(ctx context.Context, obj *ent.Product) (*models.FormattedPrice, error) {
+	price, err := productPrice(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	price.Localize(config.LocaleFromContext(ctx))
+	return price.Formatted, nil
+}
+
+// Products is the resolver for the products field.
+func (r *queryResolver) Products(ctx context.Context, after *entgql.Cursor[int], first *int, before *entgql.Cursor[int], last *int, orderBy *ent.ProductOrder, filter *models.ProductFilter) (*ent.ProductConnection, error) {
+	first, err := r.pageSize(first, last)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = &models.ProductFilter{}
+	}
+	if err := filter.Validate(filterFields, r.maxPageSize); err != nil {
+		return nil, err
+	}
+
+	return r.client.Product.Query().
+		Where(services.FilterPredicates(*filter)...).
+		WithCategory().
+		Paginate(ctx, after, first, before, last,
+			ent.WithProductOrder(orderBy),
+		)
+}
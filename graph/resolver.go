@@ -0,0 +1,108 @@
+package graph
+
+//go:generate go run -mod=mod github.com/99designs/gqlgen
+
+import (
+	"context"
+	"entgo.io/contrib/entgql"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/services"
+	"sync"
+)
+
+const defaultPageSize = 10
+
+// filterFields are the fields of the ProductFilter input, the connections page with cursors
+var filterFields = models.ProductFilterFields{
+	Categories: "categories", SKUs: "skus", PriceLessThan: "priceLessThan", Search: "search", MinDiscount: "minDiscount",
+}
+
+// Resolver is the resolver root, the ent client backs the generated connections
+type Resolver struct {
+	client      *ent.Client
+	maxPageSize int
+}
+
+// NewSchema creates the executable GraphQL schema, maxPageSize caps first and last like MAX_PAGE_SIZE caps limit
+func NewSchema(client *ent.Client, maxPageSize int) graphql.ExecutableSchema {
+	r := &Resolver{client: client, maxPageSize: maxPageSize}
+	cfg := Config{Resolvers: r}
+	cfg.Complexity.Query.Products = func(childComplexity int, _ *entgql.Cursor[int], first *int, _ *entgql.Cursor[int], last *int, _ *ent.ProductOrder, _ *models.ProductFilter) int {
+		return r.connectionCost(childComplexity, first, last)
+	}
+	cfg.Complexity.Query.Categories = func(childComplexity int, _ *entgql.Cursor[int], first *int, _ *entgql.Cursor[int], last *int, _ *ent.CategoryOrder) int {
+		return r.connectionCost(childComplexity, first, last)
+	}
+	cfg.Complexity.Category.Products = func(childComplexity int, _ *entgql.Cursor[int], first *int, _ *entgql.Cursor[int], last *int, _ *ent.ProductOrder) int {
+		return r.connectionCost(childComplexity, first, last)
+	}
+	cfg.Complexity.Query.Nodes = func(childComplexity int, ids []*GlobalID) int {
+		return 1 + childComplexity*len(ids)
+	}
+	return pricedSchema{NewExecutableSchema(cfg)}
+}
+
+// pricesKey holds the prices of the products resolved by an operation
+type pricesKey struct{}
+
+// pricedSchema gives every operation its own prices so finalPrice and discount price a product once
+type pricedSchema struct {
+	graphql.ExecutableSchema
+}
+
+func (s pricedSchema) Exec(ctx context.Context) graphql.ResponseHandler {
+	next, prices := s.ExecutableSchema.Exec(ctx), &sync.Map{}
+	return func(ctx context.Context) *graphql.Response {
+		return next(context.WithValue(ctx, pricesKey{}, prices))
+	}
+}
+
+// productPrice prices the product once per operation, the fields of a product resolve concurrently
+func productPrice(ctx context.Context, obj *ent.Product) (models.PriceData, error) {
+	prices, ok := ctx.Value(pricesKey{}).(*sync.Map)
+	if !ok {
+		return services.ProductPrice(ctx, obj)
+	}
+	price, _ := prices.LoadOrStore(obj.ID, sync.OnceValues(func() (models.PriceData, error) {
+		return services.ProductPrice(ctx, obj)
+	}))
+	return price.(func() (models.PriceData, error))()
+}
+
+// connectionCost is the complexity of a connection, its fields are resolved once per node of the page so a
+// connection nested in another one costs its page size times the page size of its parent
+func (r *Resolver) connectionCost(childComplexity int, first, last *int) int {
+	size := min(defaultPageSize, r.maxPageSize)
+	for _, arg := range []*int{first, last} {
+		if arg != nil {
+			// the pages over maxPageSize are rejected by pageSize with a clearer error
+			size = min(max(*arg, 0), r.maxPageSize)
+		}
+	}
+	return 1 + childComplexity*size
+}
+
+// pageSize defaults first when the client asked for neither first nor last and rejects pages over maxPageSize
+func (r *Resolver) pageSize(first, last *int) (*int, error) {
+	var errs models.ValidationErrors
+	for _, arg := range []struct {
+		name  string
+		value *int
+	}{{"first", first}, {"last", last}} {
+		if arg.value != nil && (*arg.value < 0 || *arg.value > r.maxPageSize) {
+			errs = append(errs, models.FieldError{Field: arg.name, Message: fmt.Sprintf("must be between 0 and %d", r.maxPageSize)})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if first == nil && last == nil {
+		size := min(defaultPageSize, r.maxPageSize)
+		first = &size
+	}
+	return first, nil
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/services"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// productsETag identifies a priced result set. It changes when a product is added, removed, updated or moved to a
+// renamed category, when a promotion starts or ends since that changes the final prices, and with the ?fields=
+// selection since every selection renders another body
+func productsETag(promotionVersion string, fields fieldSet, meta models.Meta, products ...models.Product) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%d|%d|%d|%d", promotionVersion, fields, meta.TotalRecords, meta.Page, meta.TotalPages, meta.Limit)
+	for _, pd := range products {
+		fmt.Fprintf(h, "|%d:%d:%s", pd.ID, pd.UpdatedAt.UnixNano(), pd.Category)
+		if pd.CategoryDetails != nil {
+			fmt.Fprintf(h, ":%d", pd.CategoryDetails.UpdatedAt.UnixNano())
+		}
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// cacheMaxAge is how long the shared caches may keep a priced response, it never goes past the next promotion
+// start or end so a CDN doesn't serve stale prices
+func (h *Handler) cacheMaxAge(now time.Time) time.Duration {
+	maxAge := h.maxAge
+	if next, ok := services.NextPromotionChange(now); ok {
+		maxAge = min(maxAge, next.Sub(now))
+	}
+	return maxAge
+}
+
+// notModified sets the caching headers of a GET response and reports whether the If-None-Match header of the
+// request already matches the version, in which case the 304 was sent and the handler has nothing left to do.
+// The api version, the locale and the format are part of the etag and Vary is set by the config.Version and
+// config.Localization middlewares, since the same url renders another body when the Accept header asks for v2, CSV
+// or MessagePack or the Accept-Language header for another locale
+func (h *Handler) notModified(c *gin.Context, version string) bool {
+	// the tag is computed from the data rather than the bytes so it is weak
+	etag := fmt.Sprintf(`W/"%s-%s-%s"`, config.APIVersion(c), config.Locale(c), version)
+	if format := config.ResponseFormat(c); format != gin.MIMEJSON {
+		// every format renders another body from the same data
+		etag = fmt.Sprintf(`W/"%s-%s-%s-%s"`, config.APIVersion(c), config.Locale(c), path.Base(format), version)
+	}
+	seconds := int(math.Ceil(h.cacheMaxAge(time.Now()).Seconds()))
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", max(seconds, 0)))
+
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches implements the weak comparison If-None-Match uses, the header may list several tags or be *
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"net/http"
+)
+
+// FetchCategories lists every category with the number of products assigned to it
+func (h *Handler) FetchCategories(c *gin.Context) {
+	resp, err := h.rs.FetchCategories(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// FetchCategory returns the category in the path
+func (h *Handler) FetchCategory(c *gin.Context) {
+	resp, err := h.rs.FetchCategory(c, c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// CreateCategory adds a new category
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var input models.CategoryInput
+	if err := bindJSON(c, &input); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.CreateCategory(c, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "created", http.StatusCreated, resp)
+}
+
+// PatchCategory renames the category in the path or edits its description
+func (h *Handler) PatchCategory(c *gin.Context) {
+	var patch models.CategoryPatch
+	if err := bindJSON(c, &patch); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.PatchCategory(c, c.Param("name"), patch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// DeleteCategory removes the category in the path, it has to be empty
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	if err := h.rs.DeleteCategory(c, c.Param("name")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MergeCategory moves every product of the category in the path into another one and deletes it
+func (h *Handler) MergeCategory(c *gin.Context) {
+	var merge models.CategoryMerge
+	if err := bindJSON(c, &merge); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.MergeCategory(c, c.Param("name"), merge)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+)
+
+// exportPageSize is the number of products fetched and flushed to the client at once
+const exportPageSize = 500
+
+// productEncoder writes a page of products in one of the export formats,
+// Flush writes out whatever is still buffered once the export is done
+type productEncoder interface {
+	Encode(models.Products) error
+	Flush() error
+}
+
+type csvProductEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVProductEncoder(w io.Writer) (*csvProductEncoder, error) {
+	enc := &csvProductEncoder{w: csv.NewWriter(w)}
+	if err := enc.w.Write(models.ProductColumns); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+func (enc *csvProductEncoder) Encode(products models.Products) error {
+	for _, pd := range products {
+		if err := enc.w.Write(pd.Record()); err != nil {
+			return err
+		}
+	}
+	return enc.Flush()
+}
+
+func (enc *csvProductEncoder) Flush() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+type ndjsonProductEncoder struct {
+	enc *json.Encoder
+}
+
+func (enc *ndjsonProductEncoder) Encode(products models.Products) error {
+	for _, pd := range products {
+		// json.Encoder ends every value with a new line
+		if err := enc.enc.Encode(pd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (enc *ndjsonProductEncoder) Flush() error {
+	return nil
+}
+
+// ExportProducts streams every priced product as CSV or NDJSON, gzipped when the client accepts it
+func (h *Handler) ExportProducts(c *gin.Context) {
+	params := newQueryBinder(c, "format")
+	format := params.String("format", false, maxSearchLength)
+	if format == "" {
+		format = models.ImportFormatCSV
+	}
+
+	contentType := map[string]string{
+		models.ImportFormatCSV:    "text/csv; charset=utf-8",
+		models.ImportFormatNDJSON: "application/x-ndjson",
+	}[format]
+	if contentType == "" {
+		params.fail("format", "must be one of csv, ndjson")
+	}
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, format))
+	c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+	var w io.Writer = c.Writer
+	var gz *gzip.Writer
+	if config.NegotiateEncoding(c.GetHeader("Accept-Encoding"), "gzip", "identity") == "gzip" {
+		c.Header("Content-Encoding", "gzip")
+		gz = gzip.NewWriter(c.Writer)
+		w = gz
+	}
+
+	var enc productEncoder
+	switch format {
+	case models.ImportFormatCSV:
+		csvEnc, err := newCSVProductEncoder(w)
+		if err != nil {
+			exportFailed(c, err)
+			return
+		}
+		enc = csvEnc
+	case models.ImportFormatNDJSON:
+		enc = &ndjsonProductEncoder{enc: json.NewEncoder(w)}
+	}
+
+	err := h.rs.EachProductPage(c, exportPageSize, func(products models.Products) error {
+		if err := enc.Encode(products); err != nil {
+			return err
+		}
+		if gz != nil {
+			if err := gz.Flush(); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		err = enc.Flush()
+	}
+
+	switch {
+	case err != nil && !c.Writer.Written():
+		// nothing was sent yet so the client can still get a proper error
+		exportFailed(c, err)
+	case err != nil:
+		// the status and part of the body are already sent, all we can do is cut the stream short
+		logrus.WithError(err).Error("product export aborted")
+		c.Abort()
+	case gz != nil:
+		if err := gz.Close(); err != nil {
+			logrus.WithError(err).Error("failed to close the gzip stream")
+		}
+	}
+}
+
+// exportFailed drops the headers of the file before the error is rendered, the problem would otherwise be sent as a
+// gzipped CSV attachment
+func exportFailed(c *gin.Context, err error) {
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	c.Writer.Header().Del("Content-Encoding")
+	respondError(c, err)
+}
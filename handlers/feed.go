@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/feed"
+)
+
+// FetchProductFeed streams the Google Merchant Center product feed as XML or TSV,
+// the products that break the feed spec are left out and logged
+func (h *Handler) FetchProductFeed(c *gin.Context) {
+	params := newQueryBinder(c, "format")
+	format := params.String("format", false, maxSearchLength)
+	if format == "" {
+		format = feed.FormatXML
+	}
+
+	contentType := map[string]string{
+		feed.FormatXML: "application/xml; charset=utf-8",
+		feed.FormatTSV: "text/tab-separated-values; charset=utf-8",
+	}[format]
+	if contentType == "" {
+		params.fail("format", "must be one of xml, tsv")
+	}
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="products.%s"`, format))
+
+	w, err := feed.NewWriter(format, c.Writer, feed.ChannelFromEnv())
+	if err != nil {
+		exportFailed(c, err)
+		return
+	}
+
+	report, err := feed.Render(c, h.rs.EachProductPage, w)
+	switch {
+	case err != nil && !c.Writer.Written():
+		// nothing was sent yet so the client can still get a proper error instead of an empty feed
+		exportFailed(c, err)
+		return
+	case err != nil:
+		// the feed header is already sent, all we can do is cut the stream short
+		logrus.WithError(err).Error("product feed aborted")
+		c.Abort()
+		return
+	}
+	for _, skipped := range report.Skipped {
+		logrus.WithField("id", skipped.ID).WithError(skipped.Errors).Warn("product left out of the feed")
+	}
+}
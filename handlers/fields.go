@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/tonymj76/mytheresa-test/models"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// fieldSet is a tree of json field names, a nil child selects the whole value of the field
+type fieldSet map[string]fieldSet
+
+// productShape holds every path a ?fields= selection may name, e.g. sku or price.final
+var productShape = jsonShape(reflect.TypeOf(models.Product{}))
+
+// jsonShape lists the json fields of a struct, the structs of the models package are walked into
+// while the other values such as time.Time or null.String are leaves
+func jsonShape(t reflect.Type) fieldSet {
+	shape := fieldSet{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft.PkgPath() == t.PkgPath() {
+			shape[name] = jsonShape(ft)
+		} else {
+			shape[name] = nil
+		}
+	}
+	return shape
+}
+
+// Fields reads the ?fields= sparse fieldset, every dotted path has to exist in shape. It returns nil when the
+// parameter is not set, a path that selects a whole object wins over the paths of its fields
+func (b *queryBinder) Fields(shape fieldSet) fieldSet {
+	paths := b.List("fields", maxFilterValues)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	selected := fieldSet{}
+	for _, path := range paths {
+		if !shape.has(path) {
+			b.fail("fields", fmt.Sprintf("unknown field %s", path))
+			continue
+		}
+		selected.add(strings.Split(path, "."))
+	}
+	return selected
+}
+
+func (fs fieldSet) has(path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		children, ok := fs[name]
+		if !ok {
+			return false
+		}
+		fs = children
+	}
+	return true
+}
+
+func (fs fieldSet) add(names []string) {
+	children, seen := fs[names[0]]
+	switch {
+	case len(names) == 1:
+		fs[names[0]] = nil
+	case seen && children == nil:
+		// the whole object is already selected
+	default:
+		if children == nil {
+			children = fieldSet{}
+			fs[names[0]] = children
+		}
+		children.add(names[1:])
+	}
+}
+
+// top returns the sorted top level fields of the selection
+func (fs fieldSet) top() []string {
+	return slices.Sorted(maps.Keys(fs))
+}
+
+// String is the canonical form of the selection, the same set of paths always gives the same string
+func (fs fieldSet) String() string {
+	var paths []string
+	for _, name := range fs.top() {
+		if fs[name] == nil {
+			paths = append(paths, name)
+			continue
+		}
+		for _, path := range strings.Split(fs[name].String(), ",") {
+			paths = append(paths, name+"."+path)
+		}
+	}
+	return strings.Join(paths, ",")
+}
+
+// project translates v to the locale, renders it to json and keeps the selected fields only, the fields left out by
+// omitempty stay absent. The shaped maps are no longer Localizable so config.JSON can't translate them afterwards
+func (fs fieldSet) project(v models.Localizable, locale string) (map[string]any, error) {
+	v.Localize(locale)
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to shape the response: %w", err)
+	}
+
+	var object map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// keep the numbers as they were written instead of turning them into floats
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("failed to shape the response: %w", err)
+	}
+	return fs.prune(object), nil
+}
+
+func (fs fieldSet) prune(object map[string]any) map[string]any {
+	shaped := make(map[string]any, len(fs))
+	for name, children := range fs {
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+		if nested, isObject := value.(map[string]any); isObject && children != nil {
+			value = children.prune(nested)
+		}
+		shaped[name] = value
+	}
+	return shaped
+}
+
+// projectProducts shapes every product of a listing
+func (fs fieldSet) projectProducts(products models.Products, locale string) ([]map[string]any, error) {
+	shaped := make([]map[string]any, 0, len(products))
+	for i := range products {
+		object, err := fs.project(&products[i], locale)
+		if err != nil {
+			return nil, err
+		}
+		shaped = append(shaped, object)
+	}
+	return shaped, nil
+}
+
+// shapedProducts is a page of the listing reduced to the selected fields
+type shapedProducts struct {
+	Products []map[string]any `json:"products"`
+	Meta     models.Meta      `json:"meta"`
+	fields   fieldSet
+}
+
+func (sp *shapedProducts) PageItems() any {
+	return sp.Products
+}
+
+func (sp *shapedProducts) PageMeta() models.Meta {
+	return sp.Meta
+}
+
+// Columns are the selected paths, e.g. price.final
+func (sp *shapedProducts) Columns() []string {
+	return strings.Split(sp.fields.String(), ",")
+}
+
+func (sp *shapedProducts) Records() [][]string {
+	columns := sp.Columns()
+	records := make([][]string, 0, len(sp.Products))
+	for _, object := range sp.Products {
+		record := make([]string, 0, len(columns))
+		for _, path := range columns {
+			record = append(record, cell(object, strings.Split(path, ".")))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// cell renders the value at the path as a CSV field, the missing and null values are empty and the objects are JSON
+func cell(object map[string]any, names []string) string {
+	value, ok := object[names[0]]
+	if nested, isObject := value.(map[string]any); ok && isObject && len(names) > 1 {
+		return cell(nested, names[1:])
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+}
+
+// shapedBatch is a batchGet response reduced to the selected fields
+type shapedBatch struct {
+	Products []map[string]any `json:"products"`
+	NotFound []string         `json:"not_found"`
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/graph"
+	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/services"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+type HandlerOption func(h *Handler)
+
+// WithGraphQL serves the GraphQL schema generated from the ent types on top of the given client. The operations
+// costing more than GRAPHQL_MAX_COMPLEXITY are rejected before they run, the connections cost their page size so the
+// nested ones can't fan out without bound
+func WithGraphQL(client *ent.Client) HandlerOption {
+	return func(h *Handler) {
+		srv := handler.NewDefaultServer(graph.NewSchema(client, h.maxPageSize))
+		srv.Use(extension.FixedComplexityLimit(config.GetEnvInt("GRAPHQL_MAX_COMPLEXITY", 5000)))
+		srv.SetErrorPresenter(presentGraphQLError)
+		h.graphql = srv
+	}
+}
+
+// presentGraphQLError gives the resolver errors the same codes as the REST api and hides the internal details,
+// the errors gqlgen raises itself while parsing and validating the query are left as they are
+func presentGraphQLError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+
+	apiErr := services.ClassifyError(gqlErr.Err)
+	if apiErr.Kind != models.ErrorKindValidation {
+		gqlErr.Message = apiErr.Detail
+	}
+	if apiErr.Kind == models.ErrorKindInternal {
+		logrus.WithError(gqlErr.Err).WithField("code", apiErr.Code).Error("graphql request failed")
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]any{}
+	}
+	gqlErr.Extensions["code"] = apiErr.Code
+	if len(apiErr.Fields) > 0 {
+		gqlErr.Extensions["errors"] = apiErr.Fields
+	}
+	return gqlErr
+}
+
+// GraphQL runs the GraphQL queries sent as POST bodies or GET query parameters
+func (h *Handler) GraphQL(c *gin.Context) {
+	if h.graphql == nil {
+		config.Problem(c, models.NewError(models.ErrorKindNotFound, "graphql_disabled", "graphql is not enabled"))
+		return
+	}
+	h.graphql.ServeHTTP(c.Writer, c.Request.WithContext(config.WithLocale(c.Request.Context(), config.Locale(c))))
+}
+
+// GraphQLPlayground serves the in-browser IDE to explore the GraphQL schema
+func (h *Handler) GraphQLPlayground(c *gin.Context) {
+	playground.Handler("mytheresa products", "/api/graphql").ServeHTTP(c.Writer, c.Request)
+}
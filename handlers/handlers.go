@@ -1,21 +1,47 @@
 package handlers
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
 	"github.com/tonymj76/mytheresa-test/services"
 	"net/http"
-	"strconv"
+	"slices"
+	"time"
+)
+
+const (
+	defaultPageSize    = 10
+	maxPage            = models.MaxPage
+	maxFilterValues    = models.MaxFilterValues
+	maxSearchLength    = models.MaxSearchLength
+	defaultSuggestions = 5
+	maxSuggestions     = 20
+	maxSKULength       = 64
+	defaultCacheMaxAge = 60
 )
 
 type Handler struct {
-	rs services.ProductEnsurer
+	rs          services.ProductEnsurer
+	maxPageSize int
+	maxAge      time.Duration
+	graphql     http.Handler
+	// streamHeartbeat is how often an idle price stream sends a comment
+	streamHeartbeat time.Duration
 }
 
-func NewRegisteredHandler(rs services.ProductEnsurer) *Handler {
-	return &Handler{
-		rs: rs,
+func NewRegisteredHandler(rs services.ProductEnsurer, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		rs:              rs,
+		maxPageSize:     config.MaxPageSize(),
+		maxAge:          time.Duration(config.GetEnvInt("CACHE_MAX_AGE", defaultCacheMaxAge)) * time.Second,
+		streamHeartbeat: time.Duration(max(config.GetEnvInt("STREAM_HEARTBEAT_INTERVAL", defaultStreamHeartbeat), 1)) * time.Second,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // Test testing if the service is running
@@ -23,32 +49,228 @@ func (h *Handler) Test(c *gin.Context) {
 	config.JSON(c, "successful", http.StatusOK, map[string]string{"testing": "server is running..."})
 }
 
+// productFilterFields are the query parameters of the product filter
+var productFilterFields = models.ProductFilterFields{
+	Categories: "category", SKUs: "sku", PriceLessThan: "priceLessThan", Search: "q", MinDiscount: "minDiscount",
+	Page: "page", Limit: "limit",
+}
+
 // FetchProducts fetches the product that is associated with the query parameters
 func (h *Handler) FetchProducts(c *gin.Context) {
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
-	category := c.Query("category")
-	priceLessThanStr := c.Query("priceLessThan")
+	params := newQueryBinder(c, "page", "limit", "category", "sku", "priceLessThan", "q", "onSale", "minDiscount", "fields")
+	fields := params.Fields(productShape)
+
+	filter := models.ProductFilter{
+		Page:          params.Whole("page", 1),
+		Limit:         params.Whole("limit", min(defaultPageSize, h.maxPageSize)),
+		Categories:    params.List("category", 0),
+		SKUs:          params.List("sku", 0),
+		PriceLessThan: params.Whole("priceLessThan", 0),
+		Search:        params.String("q", false, 0),
+		OnSale:        params.Bool("onSale"),
+		MinDiscount:   params.Whole("minDiscount", 0),
+		Fields:        fields.top(),
+	}
+	params.check(filter.Validate(productFilterFields, h.maxPageSize))
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// read before the prices so a promotion that starts meanwhile gives the next request a new etag
+	promotionVersion := services.PromotionVersion(time.Now())
+	resp, err := h.rs.FilterProduct(c, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if h.notModified(c, productsETag(promotionVersion, fields, resp.Meta, resp.Products...)) {
+		return
+	}
+	if fields == nil {
+		config.JSON(c, "successful", http.StatusOK, resp)
+		return
+	}
+	shaped, err := fields.projectProducts(resp.Products, config.Locale(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, &shapedProducts{Products: shaped, Meta: resp.Meta, fields: fields})
+}
+
+// SuggestProducts returns autocomplete suggestions for the prefix the shopper has typed so far
+func (h *Handler) SuggestProducts(c *gin.Context) {
+	params := newQueryBinder(c, "prefix", "limit")
+
+	prefix := params.String("prefix", true, maxSearchLength)
+	limit := params.Int("limit", defaultSuggestions, 1, maxSuggestions)
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.SuggestProducts(c, prefix, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// respondError renders the errors returned by the service as problem details with the matching status code
+func respondError(c *gin.Context, err error) {
+	config.Problem(c, services.ClassifyError(err))
+}
+
+// FetchProduct returns the product in the path with its discounted price
+func (h *Handler) FetchProduct(c *gin.Context) {
+	params := newQueryBinder(c, "include", "fields")
+	include := params.Include("category")
+	fields := params.Fields(productShape)
+	sku, err := pathSKU(c)
+	if err == nil {
+		err = params.Err()
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	promotionVersion := services.PromotionVersion(time.Now())
+	resp, err := h.rs.FetchProduct(c, sku, slices.Contains(include, "category"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if h.notModified(c, productsETag(promotionVersion, fields, models.Meta{}, *resp)) {
+		return
+	}
+	if fields == nil {
+		config.JSON(c, "successful", http.StatusOK, resp)
+		return
+	}
+	shaped, err := fields.project(resp, config.Locale(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, shaped)
+}
+
+// ProductAction dispatches the custom methods of the products collection such as POST /products:batchGet,
+// gin reports everything after "/products" as the action so it still starts with the colon
+func (h *Handler) ProductAction(c *gin.Context) {
+	switch c.Param("action") {
+	case ":batchGet":
+		h.BatchGetProducts(c)
+	default:
+		config.Problem(c, models.NewError(models.ErrorKindNotFound, "unknown_action", fmt.Sprintf("unknown action %s", c.Param("action"))))
+	}
+}
+
+// BatchGetProducts prices up to 200 products at once, keeping the order of the requested skus
+func (h *Handler) BatchGetProducts(c *gin.Context) {
+	var input models.BatchGetRequest
+	params := newQueryBinder(c, "fields")
+	fields := params.Fields(productShape)
+	err := params.Err()
+	if err == nil {
+		err = bindJSON(c, &input)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.BatchGetProducts(c, input.SKUs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if fields == nil {
+		config.JSON(c, "successful", http.StatusOK, resp)
+		return
+	}
+	shaped, err := fields.projectProducts(resp.Products, config.Locale(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, &shapedBatch{Products: shaped, NotFound: resp.NotFound})
+}
+
+// CreateProduct adds a new product under the sku in the path
+func (h *Handler) CreateProduct(c *gin.Context) {
+	var input models.ProductInput
+	sku, err := pathSKU(c)
+	if err == nil {
+		err = bindJSON(c, &input)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.CreateProduct(c, sku, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "created", http.StatusCreated, resp)
+}
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+// ReplaceProduct overwrites the name, category and price of the product in the path
+func (h *Handler) ReplaceProduct(c *gin.Context) {
+	var input models.ProductInput
+	sku, err := pathSKU(c)
+	if err == nil {
+		err = bindJSON(c, &input)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 10
+	resp, err := h.rs.ReplaceProduct(c, sku, input)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
 
-	priceLessThan, err := strconv.Atoi(priceLessThanStr)
-	if err != nil || priceLessThan < 1 {
-		priceLessThan = 0
+// PatchProduct updates only the fields sent in the body of the product in the path
+func (h *Handler) PatchProduct(c *gin.Context) {
+	var patch models.ProductPatch
+	sku, err := pathSKU(c)
+	if err == nil {
+		err = bindJSON(c, &patch)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	resp, err := h.rs.FilterProduct(c, category, priceLessThan, page, limit)
+	resp, err := h.rs.PatchProduct(c, sku, patch)
 	if err != nil {
-		config.JSON(c, "failed", http.StatusInternalServerError, err)
+		respondError(c, err)
 		return
 	}
 	config.JSON(c, "successful", http.StatusOK, resp)
 }
+
+// DeleteProduct removes the product in the path
+func (h *Handler) DeleteProduct(c *gin.Context) {
+	sku, err := pathSKU(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.rs.DeleteProduct(c, sku); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
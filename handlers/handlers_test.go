@@ -1,13 +1,33 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"expvar"
 	"fmt"
+	"io"
+	"maps"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,12 +36,24 @@ import (
 	"github.com/ory/dockertest/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/tonymj76/mytheresa-test/config"
 	"github.com/tonymj76/mytheresa-test/ent"
 	"github.com/tonymj76/mytheresa-test/ent/enttest"
-	"github.com/tonymj76/mytheresa-test/ent/migrate"
+	"github.com/tonymj76/mytheresa-test/graph"
 	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/openapi"
+	"github.com/tonymj76/mytheresa-test/rpc"
+	"github.com/tonymj76/mytheresa-test/rpc/productpb"
 	"github.com/tonymj76/mytheresa-test/seed"
 	"github.com/tonymj76/mytheresa-test/services"
+	"github.com/tonymj76/mytheresa-test/storage"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 var (
@@ -34,6 +66,75 @@ type ProductTestData struct {
 	Data models.ProductsResponse
 }
 
+type SingleProductTestData struct {
+	Data models.Product
+}
+
+type SingleCategoryTestData struct {
+	Data models.Category
+}
+
+type BatchGetTestData struct {
+	Data models.BatchGetResponse
+}
+
+type ImportTestData struct {
+	Data models.ImportJob
+}
+
+type ValidationTestData struct {
+	Errors models.ValidationErrors
+}
+
+type V2ProductsTestData struct {
+	Data  []models.Product
+	Meta  *models.Meta
+	Links config.Links
+}
+
+type ProblemTestData struct {
+	Type          string
+	Status        int
+	Detail        string
+	Code          string
+	CorrelationID string
+	Errors        models.ValidationErrors
+}
+
+// failingService fails every product listing with a raw database error, the other methods are not implemented
+type failingService struct {
+	services.ProductEnsurer
+}
+
+func (failingService) FilterProduct(context.Context, models.ProductFilter) (*models.ProductsResponse, error) {
+	return nil, fmt.Errorf("failed counting products: %w", errors.New(`pq: relation "products" does not exist`))
+}
+
+type SuggestionTestData struct {
+	Data models.Suggestions
+}
+
+type GraphQLProductsTestData struct {
+	Data struct {
+		Products struct {
+			TotalCount int
+			PageInfo   struct {
+				HasNextPage bool
+			}
+			Edges []struct {
+				Node struct {
+					SKU        string
+					FinalPrice int
+					Discount   *string
+				}
+			}
+		}
+	}
+	Errors []struct {
+		Message string
+	}
+}
+
 func TestMain(m *testing.M) {
 	code := 0
 	defer func() {
@@ -82,7 +183,6 @@ func TestMain(m *testing.M) {
 	link = fmt.Sprintf("postgres://%s:%s@localhost:%s/%s?sslmode=disable", src["user"], src["password"], dbPort, src["db"])
 	opts := []enttest.Option{
 		enttest.WithOptions(ent.Log(nil)),
-		enttest.WithMigrateOptions(migrate.WithGlobalUniqueID(true)),
 	}
 
 	if err := pool.Retry(func() error {
@@ -96,6 +196,9 @@ func TestMain(m *testing.M) {
 	}
 
 	db = enttest.Open(nil, "postgres", link, opts...)
+	if err := storage.Migrate(context.Background(), db); err != nil {
+		log.WithError(err).Fatal("failed to run sql migrations")
+	}
 	filePath := filepath.Join("testdata", "test_fetch_product.json")
 	if err := seed.SeedDatabase(db, filePath); err != nil {
 		log.WithError(err).Error("failed to seed database")
@@ -109,12 +212,9 @@ func TestMain(m *testing.M) {
 	code = m.Run()
 }
 
+// setRouter serves the production routes with every response checked against the OpenAPI document
 func setRouter(h *Handler) *gin.Engine {
-	router := gin.Default()
-	apiGroupRoute := router.Group("/api")
-	apiGroupRoute.GET("/products", h.FetchProducts)
-	apiGroupRoute.GET("/", h.Test)
-	return router
+	return NewRouter(h, openapi.Options{Responses: true})
 }
 
 func TestHandler_FetchProducts(t *testing.T) {
@@ -227,3 +327,2243 @@ func TestHandler_FetchProducts(t *testing.T) {
 	//	"response":    w.Body.String(),
 	//}).Info("Response received")
 }
+
+func TestHandler_SearchProducts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		queryParam    string
+		wantSKUs      []string
+		wantHighlight string
+	}{
+		{name: "search by product name", queryParam: "?q=ashlington", wantSKUs: []string{"000003"}, wantHighlight: "<mark>Ashlington</mark> leather ankle boots"},
+		{name: "search matches every term", queryParam: "?q=leather%20boots", wantSKUs: []string{"000001", "000002", "000003"}},
+		{name: "search by category name", queryParam: "?q=sandals", wantSKUs: []string{"000004"}},
+		{name: "search combined with category", queryParam: "?q=leather&category=sneakers", wantSKUs: []string{"000005"}},
+		{name: "search without match", queryParam: "?q=handbag", wantSKUs: nil},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/api/products%s", tc.queryParam), nil)
+			route.ServeHTTP(w, req)
+
+			var responseMap ProductTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+
+			var skus []string
+			for _, prod := range responseMap.Data.Products {
+				skus = append(skus, prod.SKU)
+			}
+			assert.ElementsMatch(t, tc.wantSKUs, skus, "Unexpected search result")
+			assert.Equal(t, len(tc.wantSKUs), responseMap.Data.Meta.TotalRecords, "Unexpected total records")
+			if tc.wantHighlight != "" {
+				assert.Equal(t, tc.wantHighlight, responseMap.Data.Products[0].Highlight.String, "Unexpected highlight")
+			}
+		})
+	}
+}
+
+func TestHandler_SuggestProducts(t *testing.T) {
+	testCases := []struct {
+		name       string
+		queryParam string
+		wantStatus int
+		want       []models.Suggestion
+	}{
+		{name: "missing prefix", queryParam: "", wantStatus: http.StatusBadRequest},
+		{
+			name: "prefix of a product name", queryParam: "?prefix=ashl", wantStatus: http.StatusOK,
+			want: []models.Suggestion{{Type: models.SuggestionTypeProduct, Text: "Ashlington leather ankle boots", SKU: null.StringFrom("000003")}},
+		},
+		{
+			name: "typo in the prefix", queryParam: "?prefix=ashk", wantStatus: http.StatusOK,
+			want: []models.Suggestion{{Type: models.SuggestionTypeProduct, Text: "Ashlington leather ankle boots", SKU: null.StringFrom("000003")}},
+		},
+		{
+			name: "category suggestion", queryParam: "?prefix=sanda&limit=1", wantStatus: http.StatusOK,
+			want: []models.Suggestion{{Type: models.SuggestionTypeCategory, Text: "sandals"}},
+		},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/api/products/suggest%s", tc.queryParam), nil)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var responseMap SuggestionTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+
+			if !assert.Len(t, responseMap.Data, len(tc.want), "Unexpected number of suggestions") {
+				return
+			}
+			for i, want := range tc.want {
+				assert.Equal(t, want.Type, responseMap.Data[i].Type, "Unexpected suggestion type")
+				assert.Equal(t, want.Text, responseMap.Data[i].Text, "Unexpected suggestion text")
+				assert.Equal(t, want.SKU, responseMap.Data[i].SKU, "Unexpected suggestion sku")
+			}
+		})
+	}
+}
+
+// BenchmarkHandler_SuggestProducts reports the p95 latency of the autocomplete endpoint,
+// run it with `go test ./handlers -run=^$ -bench=SuggestProducts`
+func BenchmarkHandler_SuggestProducts(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.DebugMode)
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		b.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+	prefixes := []string{"ashl", "leath", "boo", "sanda", "nathne"}
+	latencies := make([]time.Duration, 0, b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/products/suggest?prefix="+prefixes[i%len(prefixes)], nil)
+
+		start := time.Now()
+		route.ServeHTTP(w, req)
+		latencies = append(latencies, time.Since(start))
+
+		if w.Code != http.StatusOK {
+			b.Fatalf("unexpected status code %d, response body: %s", w.Code, w.Body.String())
+		}
+	}
+	b.StopTimer()
+
+	slices.Sort(latencies)
+	p95 := latencies[(len(latencies)*95+99)/100-1]
+	b.ReportMetric(float64(p95.Microseconds())/1000, "p95-ms")
+}
+
+func TestHandler_FetchSaleProducts(t *testing.T) {
+	testCases := []struct {
+		name       string
+		queryParam string
+		wantSKUs   []string
+		wantTotal  int
+	}{
+		{name: "only products on sale", queryParam: "?onSale=true", wantSKUs: []string{"000001", "000002", "000003"}, wantTotal: 3},
+		{name: "minimum discount met by the category", queryParam: "?minDiscount=30", wantSKUs: []string{"000001", "000002", "000003"}, wantTotal: 3},
+		{name: "minimum discount above every promotion", queryParam: "?onSale=true&minDiscount=31", wantSKUs: nil, wantTotal: 0},
+		{name: "sale combined with price filter", queryParam: "?onSale=true&priceLessThan=89000", wantSKUs: []string{"000001", "000003"}, wantTotal: 2},
+		{name: "sale paginated", queryParam: "?onSale=true&limit=2&page=2", wantSKUs: []string{"000003"}, wantTotal: 3},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/api/products%s", tc.queryParam), nil)
+			route.ServeHTTP(w, req)
+
+			var responseMap ProductTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+
+			var skus []string
+			for _, prod := range responseMap.Data.Products {
+				skus = append(skus, prod.SKU)
+				assert.True(t, prod.Price.DiscountPercentage.Valid, "Expected a discount on sale product %s", prod.SKU)
+			}
+			assert.Equal(t, tc.wantSKUs, skus, "Unexpected sale products")
+			assert.Equal(t, tc.wantTotal, responseMap.Data.Meta.TotalRecords, "Unexpected total records")
+		})
+	}
+}
+
+func TestHandler_FetchProductsMultiValueFilters(t *testing.T) {
+	testCases := []struct {
+		name       string
+		queryParam string
+		wantSKUs   []string
+	}{
+		{name: "comma separated categories", queryParam: "?category=sandals,sneakers", wantSKUs: []string{"000004", "000005"}},
+		{name: "repeated categories", queryParam: "?category=sandals&category=sneakers", wantSKUs: []string{"000004", "000005"}},
+		{name: "comma separated skus", queryParam: "?sku=000001,000003", wantSKUs: []string{"000001", "000003"}},
+		{name: "skus within categories", queryParam: "?sku=000001&sku=000004&category=boots", wantSKUs: []string{"000001"}},
+		{name: "empty values are ignored", queryParam: "?category=sandals,,%20", wantSKUs: []string{"000004"}},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/api/products%s", tc.queryParam), nil)
+			route.ServeHTTP(w, req)
+
+			var responseMap ProductTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+
+			var skus []string
+			for _, prod := range responseMap.Data.Products {
+				skus = append(skus, prod.SKU)
+			}
+			assert.Equal(t, tc.wantSKUs, skus, "Unexpected products")
+		})
+	}
+}
+
+func TestHandler_FetchProductsValidation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		wantFields []string
+	}{
+		{name: "price is not a number", path: "/api/products?priceLessThan=abc", wantFields: []string{"priceLessThan"}},
+		{name: "limit above the max page size", path: "/api/products?limit=101", wantFields: []string{"limit"}},
+		{name: "negative price", path: "/api/products?priceLessThan=-1", wantFields: []string{"priceLessThan"}},
+		{name: "page above the last page allowed", path: "/api/products?page=9223372036854775807", wantFields: []string{"page"}},
+		{name: "every invalid field is listed", path: "/api/products?page=0&limit=abc&onSale=maybe&minDiscount=120&foo=bar", wantFields: []string{"foo", "page", "limit", "onSale", "minDiscount"}},
+		{name: "suggest without prefix", path: "/api/products/suggest?limit=50", wantFields: []string{"prefix", "limit"}},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, "Expected HTTP 400 Bad Request status")
+
+			var responseMap ValidationTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+
+			var fields []string
+			for _, fe := range responseMap.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.wantFields, fields, "Unexpected invalid fields")
+		})
+	}
+}
+
+func TestHandler_FetchProductsMaxPageSizeBelowOne(t *testing.T) {
+	t.Setenv("MAX_PAGE_SIZE", "0")
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/products", nil)
+	route.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+	var responseMap ProductTestData
+	if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+		t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+	}
+	assert.Equal(t, 1, responseMap.Data.Meta.Limit, "Expected the page size raised to 1")
+	assert.Len(t, responseMap.Data.Products, 1, "Unexpected number of products")
+}
+
+func TestHandler_V2Envelope(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		accept      string
+		wantStatus  int
+		wantVersion string
+		wantSKUs    []string
+		wantMeta    *models.Meta
+		wantLinks   config.Links
+	}{
+		{
+			name: "v2 listing", path: "/api/v2/products?limit=2&page=2", wantStatus: http.StatusOK, wantVersion: "v2",
+			wantSKUs: []string{"000003", "000004"},
+			wantMeta: &models.Meta{TotalRecords: 5, Page: 2, TotalPages: 3, Limit: 2},
+			wantLinks: config.Links{
+				Self:  "/api/v2/products?limit=2&page=2",
+				First: "/api/v2/products?limit=2&page=1",
+				Prev:  "/api/v2/products?limit=2&page=1",
+				Next:  "/api/v2/products?limit=2&page=3",
+				Last:  "/api/v2/products?limit=2&page=3",
+			},
+		},
+		{
+			name: "v1 route negotiated to v2", path: "/api/products?limit=2&page=3", accept: "application/vnd.mytheresa.v2+json",
+			wantStatus: http.StatusOK, wantVersion: "v2", wantSKUs: []string{"000005"},
+			wantMeta: &models.Meta{TotalRecords: 5, Page: 3, TotalPages: 3, Limit: 2},
+			wantLinks: config.Links{
+				Self:  "/api/v2/products?limit=2&page=3",
+				First: "/api/v2/products?limit=2&page=1",
+				Prev:  "/api/v2/products?limit=2&page=2",
+				Last:  "/api/v2/products?limit=2&page=3",
+			},
+		},
+		{name: "v2 single product", path: "/api/v2/products/000001", wantStatus: http.StatusOK, wantVersion: "v2", wantLinks: config.Links{Self: "/api/v2/products/000001"}},
+		{name: "v2 route asked for v1", path: "/api/v2/products", accept: "application/vnd.mytheresa.v1+json", wantStatus: http.StatusNotAcceptable},
+		{name: "v1 route without a v2 twin", path: "/api/feeds/products", accept: "application/vnd.mytheresa.v2+json", wantStatus: http.StatusNotAcceptable},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.wantVersion, w.Header().Get("API-Version"), "Unexpected api version")
+
+			// v2 has no message or status in the body
+			var envelope map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.NotContains(t, envelope, "message", "Unexpected v1 field")
+			assert.NotContains(t, envelope, "statusCode", "Unexpected v1 field")
+
+			if tc.wantSKUs == nil {
+				var responseMap struct {
+					Data  models.Product
+					Links config.Links
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+					t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+				}
+				assert.Equal(t, "000001", responseMap.Data.SKU, "Unexpected product")
+				assert.Equal(t, tc.wantLinks, responseMap.Links, "Unexpected links")
+				return
+			}
+
+			var responseMap V2ProductsTestData
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			var skus []string
+			for _, prod := range responseMap.Data {
+				skus = append(skus, prod.SKU)
+			}
+			assert.Equal(t, tc.wantSKUs, skus, "Unexpected products")
+			assert.Equal(t, tc.wantMeta, responseMap.Meta, "Unexpected meta")
+			assert.Equal(t, tc.wantLinks, responseMap.Links, "Unexpected links")
+		})
+	}
+}
+
+func TestHandler_V2NegotiationLogsOnce(t *testing.T) {
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	// the logger writes to gin.DefaultWriter when the router is built
+	var logs bytes.Buffer
+	gin.DefaultWriter = &logs
+	defer func() { gin.DefaultWriter = os.Stdout }()
+	route := setRouter(NewRegisteredHandler(service))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/products/999999", nil)
+	req.Header.Set("Accept", "application/vnd.mytheresa.v2+json")
+	route.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	assert.Equal(t, "v2", w.Header().Get("API-Version"), "Expected the v2 twin")
+
+	var problem config.ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+	}
+	assert.Len(t, w.Header().Values(config.CorrelationIDHeader), 1, "Expected a single correlation id")
+	assert.Equal(t, w.Header().Get(config.CorrelationIDHeader), problem.CorrelationID, "Expected the correlation id of the first pass")
+	assert.Equal(t, 1, strings.Count(logs.String(), "/api/products/999999"), "Expected the request logged once, logs: %s", logs.String())
+	assert.NotContains(t, logs.String(), "/api/v2/products/999999", "Expected the path the client asked for in the log")
+}
+
+func TestHandler_SparseFieldsets(t *testing.T) {
+	testCases := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		wantStatus    int
+		wantFields    []string
+		wantPrice     []string
+		wantErrFields []string
+	}{
+		{name: "listing", method: http.MethodGet, path: "/api/products?sku=000001&fields=sku,name,price.final", wantStatus: http.StatusOK, wantFields: []string{"name", "price", "sku"}, wantPrice: []string{"final"}},
+		{name: "whole price wins", method: http.MethodGet, path: "/api/products?sku=000001&fields=price.final,price&fields=sku", wantStatus: http.StatusOK, wantFields: []string{"price", "sku"}, wantPrice: []string{"currency", "discount_ends_at", "discount_percentage", "discount_starts_at", "final", "formatted", "original"}},
+		{name: "v2 listing", method: http.MethodGet, path: "/api/v2/products?sku=000001&fields=sku,price.final", wantStatus: http.StatusOK, wantFields: []string{"price", "sku"}, wantPrice: []string{"final"}},
+		{name: "single product", method: http.MethodGet, path: "/api/products/000001?fields=sku,category_details.name&include=category", wantStatus: http.StatusOK, wantFields: []string{"category_details", "sku"}},
+		{name: "batch get", method: http.MethodPost, path: "/api/products:batchGet?fields=name", body: `{"skus":["000001"]}`, wantStatus: http.StatusOK, wantFields: []string{"name"}},
+		{name: "unknown fields", method: http.MethodGet, path: "/api/products?fields=sku,colour,price.vat", wantStatus: http.StatusBadRequest, wantErrFields: []string{"unknown field colour", "unknown field price.vat"}},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+			if tc.wantStatus != http.StatusOK {
+				var responseMap ProblemTestData
+				if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+					t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+				}
+				var messages []string
+				for _, fe := range responseMap.Errors {
+					messages = append(messages, fe.Message)
+				}
+				assert.Equal(t, tc.wantErrFields, messages, "Unexpected errors")
+				return
+			}
+
+			var responseMap struct {
+				Data json.RawMessage
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			// the listings hold the products in an array, under products in v1
+			var page struct {
+				Products []map[string]json.RawMessage
+			}
+			var products []map[string]json.RawMessage
+			switch {
+			case json.Unmarshal(responseMap.Data, &products) == nil:
+			case json.Unmarshal(responseMap.Data, &page) == nil && page.Products != nil:
+				products = page.Products
+			default:
+				var product map[string]json.RawMessage
+				if err := json.Unmarshal(responseMap.Data, &product); err != nil {
+					t.Fatalf("failed to unmarshal product: %v, response body: %s", err, w.Body.String())
+				}
+				products = append(products, product)
+			}
+
+			if !assert.Len(t, products, 1, "Unexpected products") {
+				return
+			}
+			assert.Equal(t, tc.wantFields, slices.Sorted(maps.Keys(products[0])), "Unexpected fields")
+			if tc.wantPrice != nil {
+				var price map[string]json.RawMessage
+				if err := json.Unmarshal(products[0]["price"], &price); err != nil {
+					t.Fatalf("failed to unmarshal price: %v, response body: %s", err, w.Body.String())
+				}
+				assert.Equal(t, tc.wantPrice, slices.Sorted(maps.Keys(price)), "Unexpected price fields")
+			}
+		})
+	}
+}
+
+func TestHandler_ContentNegotiation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		accept      string
+		wantType    string
+		wantRecords [][]string
+		wantSKU     string
+	}{
+		{
+			name: "csv listing", path: "/api/products?sku=000001", accept: "text/csv", wantType: "text/csv; charset=utf-8",
+			wantRecords: [][]string{
+				{"sku", "name", "category", "original_price", "final_price", "discount_percentage", "currency", "created_at", "updated_at"},
+				{"000001", "BV Lean leather ankle boots", "boots", "89000", "62299", "30%", "EUR"},
+			},
+		},
+		{
+			name: "csv sparse fieldset", path: "/api/v2/products?sku=000001&fields=sku,price.final", accept: "text/csv", wantType: "text/csv; charset=utf-8",
+			wantRecords: [][]string{{"price.final", "sku"}, {"62299", "000001"}},
+		},
+		{name: "msgpack listing", path: "/api/products?sku=000001", accept: "application/msgpack", wantType: "application/msgpack; charset=utf-8", wantSKU: "000001"},
+		{name: "msgpack v2 listing", path: "/api/v2/products?sku=000001", accept: "application/msgpack", wantType: "application/msgpack; charset=utf-8", wantSKU: "000001"},
+		{name: "json by default", path: "/api/products?sku=000001", accept: "*/*", wantType: "application/json; charset=utf-8"},
+		{name: "csv refused with q=0", path: "/api/products?sku=000001", accept: "text/csv;q=0, */*;q=0.1", wantType: "application/json; charset=utf-8"},
+		{name: "highest q-value wins", path: "/api/products?sku=000001", accept: "text/csv;q=0.5, application/msgpack;q=0.9", wantType: "application/msgpack; charset=utf-8", wantSKU: "000001"},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Accept", tc.accept)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			assert.Equal(t, tc.wantType, w.Header().Get("Content-Type"), "Unexpected content type")
+
+			switch {
+			case tc.wantRecords != nil:
+				records, err := csv.NewReader(w.Body).ReadAll()
+				if err != nil {
+					t.Fatalf("failed to read csv: %v", err)
+				}
+				if !assert.Len(t, records, len(tc.wantRecords), "Unexpected records") {
+					return
+				}
+				assert.Equal(t, tc.wantRecords[0], records[0], "Unexpected columns")
+				// the timestamps are left out of the comparison
+				assert.Equal(t, tc.wantRecords[1], records[1][:len(tc.wantRecords[1])], "Unexpected record")
+			case tc.wantSKU != "":
+				var document map[string]any
+				handle := &codec.MsgpackHandle{}
+				handle.RawToString = true
+				handle.MapType = reflect.TypeOf(document)
+				if err := codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&document); err != nil {
+					t.Fatalf("failed to decode msgpack: %v", err)
+				}
+				// v1 keeps the products under data.products, v2 lists them in data
+				products, ok := document["data"].([]any)
+				if !ok {
+					data, _ := document["data"].(map[string]any)
+					products, _ = data["products"].([]any)
+				}
+				if !assert.Len(t, products, 1, "Unexpected products") {
+					return
+				}
+				product, _ := products[0].(map[string]any)
+				assert.Equal(t, tc.wantSKU, product["sku"], "Unexpected product")
+			}
+		})
+	}
+}
+
+func TestHandler_Localization(t *testing.T) {
+	// the steps run in order against the same product and remove it at the end
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		acceptLanguage string
+		body           string
+		wantStatus     int
+		wantLanguage   string
+		wantName       string
+		wantCategory   string
+		wantOriginal   string
+	}{
+		{name: "create product", method: http.MethodPost, path: "/api/products/100020", body: `{"name":"Leather ankle boots","name_translations":{"de":"Knöchelhohe Lederstiefel","fr":"Bottines en cuir"},"category":"boots","price":30000}`, wantStatus: http.StatusCreated, wantLanguage: "en", wantName: "Leather ankle boots"},
+		{name: "unsupported translation", method: http.MethodPatch, path: "/api/products/100020", body: `{"name_translations":{"xx":"?"}}`, wantStatus: http.StatusBadRequest},
+		{name: "accept language", method: http.MethodGet, path: "/api/products/100020", acceptLanguage: "de-CH, en;q=0.5", wantStatus: http.StatusOK, wantLanguage: "de", wantName: "Knöchelhohe Lederstiefel", wantOriginal: "300,00\u00a0€"},
+		{name: "locale parameter wins", method: http.MethodGet, path: "/api/products?sku=100020&locale=fr", acceptLanguage: "de", wantStatus: http.StatusOK, wantLanguage: "fr", wantName: "Bottines en cuir"},
+		{name: "missing translation", method: http.MethodGet, path: "/api/products/100020?locale=it", wantStatus: http.StatusOK, wantLanguage: "it", wantName: "Leather ankle boots"},
+		{name: "unsupported locale falls back", method: http.MethodGet, path: "/api/products/100020", acceptLanguage: "ja", wantStatus: http.StatusOK, wantLanguage: "en", wantName: "Leather ankle boots", wantOriginal: "€300.00"},
+		{name: "translated category", method: http.MethodPatch, path: "/api/categories/boots?locale=de", body: `{"description_translations":{"de":"Stiefel für jedes Wetter"}}`, wantStatus: http.StatusOK, wantLanguage: "de", wantCategory: "Stiefel für jedes Wetter"},
+		{name: "translated category details", method: http.MethodGet, path: "/api/products/100020?include=category&fields=name,category_details.description&locale=de", wantStatus: http.StatusOK, wantLanguage: "de", wantName: "Knöchelhohe Lederstiefel", wantCategory: "Stiefel für jedes Wetter"},
+		{name: "remove category translations", method: http.MethodPatch, path: "/api/categories/boots", body: `{"description_translations":{}}`, wantStatus: http.StatusOK, wantLanguage: "en"},
+		{name: "untranslated category falls back", method: http.MethodGet, path: "/api/categories/boots?locale=de", wantStatus: http.StatusOK, wantLanguage: "de", wantCategory: "Ankle, knee-high and combat boots in leather and suede"},
+		{name: "seeded category translation", method: http.MethodGet, path: "/api/categories/sandals", acceptLanguage: "fr", wantStatus: http.StatusOK, wantLanguage: "fr", wantCategory: "Sandales plates, à talons et mules pour les beaux jours"},
+		{name: "seeded category missing translation", method: http.MethodGet, path: "/api/categories/sneakers?locale=fr", wantStatus: http.StatusOK, wantLanguage: "fr", wantCategory: "Low and high-top sneakers for everyday wear"},
+		{name: "seeded product translation", method: http.MethodGet, path: "/api/products/000004", acceptLanguage: "de", wantStatus: http.StatusOK, wantLanguage: "de", wantName: "Naima verzierte Wildledersandalen"},
+		{name: "malformed locale", method: http.MethodGet, path: "/api/products/100020?locale=!!", wantStatus: http.StatusBadRequest},
+		{name: "delete product", method: http.MethodDelete, path: "/api/products/100020", wantStatus: http.StatusNoContent},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			if tc.wantLanguage == "" {
+				return
+			}
+			assert.Equal(t, tc.wantLanguage, w.Header().Get("Content-Language"), "Unexpected content language")
+			assert.Contains(t, w.Header().Values("Vary"), "Accept-Language", "The response should vary on Accept-Language")
+
+			// the data is a product, a category or a listing holding the products in an array under products
+			var responseMap struct {
+				Data struct {
+					models.Product
+					Description string
+					Products    json.RawMessage
+				}
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			name := responseMap.Data.Name
+			var products []models.Product
+			if json.Unmarshal(responseMap.Data.Products, &products) == nil && len(products) > 0 {
+				name = products[0].Name
+			}
+			if tc.wantName != "" {
+				assert.Equal(t, tc.wantName, name, "Unexpected name")
+			}
+			if tc.wantOriginal != "" {
+				if assert.NotNil(t, responseMap.Data.Price.Formatted, "Expected a formatted price") {
+					assert.Equal(t, tc.wantOriginal, responseMap.Data.Price.Formatted.Original, "Unexpected formatted price")
+				}
+			}
+			if tc.wantCategory == "" {
+				return
+			}
+			description := responseMap.Data.Description
+			if responseMap.Data.CategoryDetails != nil {
+				description = responseMap.Data.CategoryDetails.Description
+			}
+			assert.Equal(t, tc.wantCategory, description, "Unexpected category description")
+		})
+	}
+}
+
+func TestHandler_ConditionalRequests(t *testing.T) {
+	// the steps run in order against the same product and remove it at the end, etag "previous" sends the etag
+	// of the previous step
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "create product", method: http.MethodPost, path: "/api/products/100010", body: `{"name":"Kanye loafers","category":"sandals","price":30000}`, wantStatus: http.StatusCreated},
+		{name: "fetch listing", method: http.MethodGet, path: "/api/products?sku=100010", wantStatus: http.StatusOK},
+		{name: "listing not modified", method: http.MethodGet, path: "/api/products?sku=100010", ifNoneMatch: "previous", wantStatus: http.StatusNotModified},
+		{name: "v2 has its own etag", method: http.MethodGet, path: "/api/v2/products?sku=100010", ifNoneMatch: "previous", wantStatus: http.StatusOK},
+		{name: "fetch product", method: http.MethodGet, path: "/api/products/100010", wantStatus: http.StatusOK},
+		{name: "product not modified", method: http.MethodGet, path: "/api/products/100010", ifNoneMatch: "previous", wantStatus: http.StatusNotModified},
+		{name: "any of the listed etags", method: http.MethodGet, path: "/api/products/100010", ifNoneMatch: `W/"v1-stale", previous`, wantStatus: http.StatusNotModified},
+		{name: "patch price", method: http.MethodPatch, path: "/api/products/100010", body: `{"price":20000}`, wantStatus: http.StatusOK},
+		{name: "updated product is sent again", method: http.MethodGet, path: "/api/products/100010", ifNoneMatch: "previous", wantStatus: http.StatusOK},
+		{name: "delete product", method: http.MethodDelete, path: "/api/products/100010", wantStatus: http.StatusNoContent},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+	previous := ""
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", strings.ReplaceAll(tc.ifNoneMatch, "previous", previous))
+			}
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			if tc.method != http.MethodGet {
+				return
+			}
+
+			etag := w.Header().Get("ETag")
+			assert.True(t, strings.HasPrefix(etag, `W/"`), "Expected a weak etag, got %q", etag)
+			assert.Regexp(t, `^public, max-age=\d+$`, w.Header().Get("Cache-Control"), "Unexpected Cache-Control")
+			assert.Contains(t, w.Header().Values("Vary"), "Accept", "The response should vary on Accept")
+			switch w.Code {
+			case http.StatusNotModified:
+				assert.Equal(t, previous, etag, "The etag should not change")
+				assert.Empty(t, w.Body.String(), "A 304 has no body")
+			case http.StatusOK:
+				if tc.ifNoneMatch != "" {
+					assert.NotEqual(t, previous, etag, "The etag should change")
+				}
+			}
+			previous = etag
+		})
+	}
+}
+
+func TestHandler_ProductCache(t *testing.T) {
+	// the steps run in order and remove the product they create at the end
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCount  int
+		wantHits   int
+		wantMisses int
+	}{
+		{name: "first listing is a miss", method: http.MethodGet, path: "/api/products?category=boots", wantStatus: http.StatusOK, wantCount: 3, wantMisses: 1},
+		{name: "same listing is a hit", method: http.MethodGet, path: "/api/products?category=boots", wantStatus: http.StatusOK, wantCount: 3, wantHits: 1},
+		{name: "ignored filter shares the entry", method: http.MethodGet, path: "/api/products?category=boots&priceLessThan=1000", wantStatus: http.StatusOK, wantCount: 3, wantHits: 1},
+		{name: "create product", method: http.MethodPost, path: "/api/products/100020", body: `{"name":"Kanye chelsea boots","category":"boots","price":40000}`, wantStatus: http.StatusCreated},
+		{name: "listing is invalidated by the create", method: http.MethodGet, path: "/api/products?category=boots", wantStatus: http.StatusOK, wantCount: 4, wantMisses: 1},
+		{name: "delete product", method: http.MethodDelete, path: "/api/products/100020", wantStatus: http.StatusNoContent},
+		{name: "listing is invalidated by the delete", method: http.MethodGet, path: "/api/products?category=boots", wantStatus: http.StatusOK, wantCount: 3, wantMisses: 1},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil), services.WithProductCache(10, time.Minute))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	cacheMetrics := func() (hits, misses int) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/debug/vars", nil)
+		expvar.Handler().ServeHTTP(w, req)
+
+		var vars struct {
+			ProductCache struct {
+				Hits   int
+				Misses int
+			} `json:"product_cache"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &vars); err != nil {
+			t.Fatalf("failed to unmarshal the metrics: %v, response body: %s", err, w.Body.String())
+		}
+		return vars.ProductCache.Hits, vars.ProductCache.Misses
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hits, misses := cacheMetrics()
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			if tc.method != http.MethodGet {
+				return
+			}
+
+			var responseMap ProductTestData
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Len(t, responseMap.Data.Products, tc.wantCount, "Unexpected number of products")
+
+			newHits, newMisses := cacheMetrics()
+			assert.Equal(t, tc.wantHits, newHits-hits, "Unexpected cache hits")
+			assert.Equal(t, tc.wantMisses, newMisses-misses, "Unexpected cache misses")
+		})
+	}
+}
+
+// serverSentEvent is an event of a text/event-stream body
+type serverSentEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+func parseServerSentEvents(body string) []serverSentEvent {
+	var events []serverSentEvent
+	for _, block := range strings.Split(body, "\n\n") {
+		var event serverSentEvent
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ":")
+			switch field {
+			case "id":
+				event.ID = value
+			case "event":
+				event.Event = value
+			case "data":
+				event.Data = value
+			}
+		}
+		if event.Event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestHandler_PriceStream(t *testing.T) {
+	service, err := services.NewRestService(services.WithCustomDB(db, nil), services.WithPriceFeed(10))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+	defer service.Close()
+	route := setRouter(NewRegisteredHandler(service))
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		route.ServeHTTP(w, req)
+		return w
+	}
+	// stream reads the events sent until the timeout
+	stream := func(path, lastEventID string, timeout time.Duration) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+		req.Header.Set("Last-Event-ID", lastEventID)
+		route.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/products/100021", `{"name":"Chelsea boots","category":"boots","price":30000}`)
+	assert.Equal(t, http.StatusCreated, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	defer send(http.MethodDelete, "/api/products/100021", "")
+
+	// reprice the product while the stream is open
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		send(http.MethodPatch, "/api/products/100021", `{"price":40000}`)
+	}()
+	w = stream("/api/products/stream?category=boots&locale=de", "", time.Second)
+	assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	events := parseServerSentEvents(w.Body.String())
+	if !assert.Len(t, events, 1, "Expected one price event, response body: %s", w.Body.String()) {
+		return
+	}
+	assert.Equal(t, "price", events[0].Event)
+	var change models.PriceChange
+	if err := json.Unmarshal([]byte(events[0].Data), &change); err != nil {
+		t.Fatalf("failed to unmarshal the event: %v", err)
+	}
+	assert.Equal(t, events[0].ID, strconv.FormatUint(change.ID, 10), "The event id should be the id of the change")
+	assert.Equal(t, "100021", change.SKU)
+	assert.Equal(t, models.PriceChangeUpdate, change.Reason)
+	assert.Equal(t, []int{21000, 28000}, []int{change.Previous.Final, change.Current.Final}, "Unexpected final prices")
+	if assert.NotNil(t, change.Current.Formatted, "Expected a formatted price") {
+		assert.Equal(t, "280,00\u00a0€", change.Current.Formatted.Final, "Unexpected formatted price")
+	}
+
+	// the client resumes after the event before it
+	previousID := strconv.FormatUint(change.ID-1, 10)
+	events = parseServerSentEvents(stream("/api/products/stream", previousID, 100*time.Millisecond).Body.String())
+	if assert.Len(t, events, 1, "Expected the missed event to be replayed") {
+		assert.Equal(t, strconv.FormatUint(change.ID, 10), events[0].ID)
+	}
+	events = parseServerSentEvents(stream("/api/products/stream?category=sneakers", previousID, 100*time.Millisecond).Body.String())
+	assert.Empty(t, events, "The events of the other categories should not be replayed")
+
+	events = parseServerSentEvents(stream("/api/products/stream", "1", 100*time.Millisecond).Body.String())
+	if assert.Len(t, events, 1, "Expected a reset event") {
+		assert.Equal(t, "reset", events[0].Event)
+	}
+
+	w = stream("/api/products/stream", "abc", 100*time.Millisecond)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "An invalid Last-Event-ID should be rejected")
+
+	// the events reach an open connection even though the responses are validated
+	server := httptest.NewServer(route)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/products/stream?category=boots", nil)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("failed to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	send(http.MethodPatch, "/api/products/100021", `{"price":50000}`)
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() && lines.Text() != "event:price" {
+	}
+	assert.Equal(t, "event:price", lines.Text(), "Expected the price event before the stream ends")
+	cancel()
+
+	disabled, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/products/stream", nil)
+	setRouter(NewRegisteredHandler(disabled)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+}
+
+// receivedWebhook is a delivery as the test receiver got it
+type receivedWebhook struct {
+	Event          models.WebhookEvent
+	ValidSignature bool
+}
+
+func TestHandler_Webhooks(t *testing.T) {
+	const secret = "whsec_test_secret_0123456789"
+	var (
+		mu       sync.Mutex
+		received []receivedWebhook
+		// the receiver fails the first delivery to be retried
+		failed bool
+		// the dead receiver fails every delivery until it is healthy
+		healthy atomic.Bool
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "."))
+		mac.Write(body)
+
+		delivery := receivedWebhook{ValidSignature: hmac.Equal(
+			[]byte(r.Header.Get("X-Webhook-Signature")),
+			[]byte("sha256="+hex.EncodeToString(mac.Sum(nil))),
+		)}
+		_ = json.Unmarshal(body, &delivery.Event)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, delivery)
+	}))
+	defer receiver.Close()
+	deadReceiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer deadReceiver.Close()
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil), services.WithWebhooks(services.WebhookOptions{
+		MaxAttempts: 2,
+		Backoff:     10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		// the receivers listen on the loopback
+		AllowPrivateTargets: true,
+	}))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+	defer service.Close()
+	route := setRouter(NewRegisteredHandler(service))
+
+	send := func(method, path, body string, data any) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		route.ServeHTTP(w, req)
+		if data != nil && w.Code < http.StatusMultipleChoices {
+			envelope := struct{ Data any }{Data: data}
+			if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+		}
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/webhooks", `{"url":"ftp://example.com","events":["product.deleted"],"secret":"short"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	var problem ProblemTestData
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err == nil {
+		var fields []string
+		for _, fe := range problem.Errors {
+			fields = append(fields, fe.Field)
+		}
+		assert.ElementsMatch(t, []string{"url", "events[0]", "secret"}, fields, "Unexpected invalid fields")
+	}
+
+	var partner, dead models.Webhook
+	w = send(http.MethodPost, "/api/webhooks", fmt.Sprintf(
+		`{"url":%q,"events":["product.created","product.repriced","product.on_sale"],"secret":%q}`, receiver.URL, secret,
+	), &partner)
+	if !assert.Equal(t, http.StatusCreated, w.Code, "Unexpected status code, response body: %s", w.Body.String()) {
+		return
+	}
+	defer send(http.MethodDelete, fmt.Sprintf("/api/webhooks/%d", partner.ID), "", nil)
+	assert.Equal(t, fmt.Sprintf("/api/webhooks/%d", partner.ID), w.Header().Get("Location"))
+	assert.Equal(t, secret, partner.Secret, "The secret should be returned on creation")
+
+	w = send(http.MethodPost, "/api/webhooks", fmt.Sprintf(`{"url":%q,"events":["product.created"]}`, deadReceiver.URL), &dead)
+	if !assert.Equal(t, http.StatusCreated, w.Code, "Unexpected status code, response body: %s", w.Body.String()) {
+		return
+	}
+	defer send(http.MethodDelete, fmt.Sprintf("/api/webhooks/%d", dead.ID), "", nil)
+	assert.True(t, strings.HasPrefix(dead.Secret, "whsec_"), "Expected a generated secret, got %q", dead.Secret)
+
+	var fetched models.Webhook
+	send(http.MethodGet, fmt.Sprintf("/api/webhooks/%d", partner.ID), "", &fetched)
+	assert.Empty(t, fetched.Secret, "The secret should only be returned on creation")
+	assert.Equal(t, []string{models.WebhookProductCreated, models.WebhookProductOnSale, models.WebhookProductRepriced}, fetched.Events)
+
+	// a new sneaker moved to the boots gets their discount
+	w = send(http.MethodPost, "/api/products/100022", `{"name":"Runner","category":"sneakers","price":10000}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	defer send(http.MethodDelete, "/api/products/100022", "", nil)
+	w = send(http.MethodPatch, "/api/products/100022", `{"category":"boots"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+	events := func() []receivedWebhook {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(received)
+	}
+	assert.Eventually(t, func() bool { return len(events()) == 3 }, 5*time.Second, 20*time.Millisecond, "Expected 3 deliveries")
+	types := map[string]models.WebhookEvent{}
+	for _, delivery := range events() {
+		assert.True(t, delivery.ValidSignature, "Invalid signature for the %s event", delivery.Event.Type)
+		types[delivery.Event.Type] = delivery.Event
+	}
+	assert.ElementsMatch(t, []string{models.WebhookProductCreated, models.WebhookProductRepriced, models.WebhookProductOnSale}, slices.Collect(maps.Keys(types)))
+	if onSale, ok := types[models.WebhookProductOnSale].Data.(map[string]any); ok {
+		assert.Equal(t, "boots", onSale["category"])
+		assert.Equal(t, "sneakers", onSale["previous_category"])
+	}
+
+	var deliveries models.WebhookDeliveries
+	w = send(http.MethodGet, fmt.Sprintf("/api/webhooks/%d/deliveries", partner.ID), "", &deliveries)
+	assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	if assert.Len(t, deliveries.Deliveries, 3, "Expected the 3 deliveries in the log") {
+		retried := 0
+		for _, delivery := range deliveries.Deliveries {
+			assert.Equal(t, models.DeliveryStatusSucceeded, delivery.Status, "Unexpected status of the %s delivery", delivery.Event)
+			assert.NotNil(t, delivery.DeliveredAt, "Missing delivery time")
+			if len(delivery.Attempts) == 2 {
+				retried++
+				assert.Equal(t, http.StatusServiceUnavailable, delivery.Attempts[0].StatusCode, "Unexpected failed attempt")
+			}
+		}
+		assert.Equal(t, 1, retried, "Expected the first delivery to be retried once")
+	}
+
+	// the dead receiver used up its 2 attempts
+	var deadLetters models.WebhookDeliveries
+	assert.Eventually(t, func() bool {
+		send(http.MethodGet, "/api/webhooks/dead-letters", "", &deadLetters)
+		return len(deadLetters.Deliveries) == 1
+	}, 5*time.Second, 20*time.Millisecond, "Expected a dead letter")
+	if len(deadLetters.Deliveries) != 1 {
+		return
+	}
+	deadLetter := deadLetters.Deliveries[0]
+	assert.Equal(t, dead.ID, deadLetter.WebhookID)
+	assert.Equal(t, models.WebhookProductCreated, deadLetter.Event)
+	assert.Len(t, deadLetter.Attempts, 2, "Unexpected number of attempts")
+
+	healthy.Store(true)
+	var redelivered models.WebhookDelivery
+	w = send(http.MethodPost, fmt.Sprintf("/api/webhooks/%d/deliveries/%d/redeliver", dead.ID, deadLetter.ID), "", &redelivered)
+	assert.Equal(t, http.StatusAccepted, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	assert.Equal(t, models.DeliveryStatusPending, redelivered.Status)
+	assert.Eventually(t, func() bool {
+		send(http.MethodGet, fmt.Sprintf("/api/webhooks/%d/deliveries?status=succeeded", dead.ID), "", &deliveries)
+		return len(deliveries.Deliveries) == 1
+	}, 5*time.Second, 20*time.Millisecond, "Expected the redelivery to succeed")
+
+	w = send(http.MethodPost, fmt.Sprintf("/api/webhooks/%d/deliveries/%d/redeliver", partner.ID, deadLetter.ID), "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, "The delivery of another webhook should not be found")
+	w = send(http.MethodGet, fmt.Sprintf("/api/webhooks/%d/deliveries?status=lost", partner.ID), "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "An unknown status should be rejected")
+
+	w = send(http.MethodDelete, fmt.Sprintf("/api/webhooks/%d", dead.ID), "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+	w = send(http.MethodGet, fmt.Sprintf("/api/webhooks/%d/deliveries", dead.ID), "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, "The deleted webhook should not be found")
+
+	strict, err := services.NewRestService(services.WithCustomDB(db, nil), services.WithWebhooks(services.WebhookOptions{}))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+	defer strict.Close()
+	strictRoute := setRouter(NewRegisteredHandler(strict))
+	for _, target := range []string{receiver.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1", "http://[::1]:8080"} {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(fmt.Sprintf(`{"url":%q,"events":["product.created"]}`, target)))
+		req.Header.Set("Content-Type", "application/json")
+		strictRoute.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "The private target %s should be rejected, response body: %s", target, w.Body.String())
+	}
+
+	disabled, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/webhooks", nil)
+	setRouter(NewRegisteredHandler(disabled)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+}
+
+func TestHandler_ProblemDetails(t *testing.T) {
+	testCases := []struct {
+		name              string
+		failing           bool
+		method            string
+		path              string
+		body              string
+		correlationID     string
+		wantStatus        int
+		wantCode          string
+		wantCorrelationID string
+		wantFields        []string
+	}{
+		{name: "not found", method: http.MethodGet, path: "/api/products/999999", correlationID: "checkout-42", wantStatus: http.StatusNotFound, wantCode: "product_not_found", wantCorrelationID: "checkout-42"},
+		{name: "validation", method: http.MethodPost, path: "/api/products/100003", body: `{"name":"Kanye","category":"hats","price":100}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_request", wantFields: []string{"category"}},
+		{name: "conflict", method: http.MethodDelete, path: "/api/categories/boots", wantStatus: http.StatusConflict, wantCode: "category_not_empty"},
+		{name: "unknown action", method: http.MethodPost, path: "/api/products:batchDelete", body: `{"skus":["000001"]}`, wantStatus: http.StatusNotFound, wantCode: "unknown_action"},
+		{name: "internal details are hidden", failing: true, method: http.MethodGet, path: "/api/products", correlationID: "not a valid id!", wantStatus: http.StatusInternalServerError, wantCode: "internal_error"},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+	failingRoute := setRouter(NewRegisteredHandler(failingService{}))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.correlationID != "" {
+				req.Header.Set("X-Correlation-ID", tc.correlationID)
+			}
+			if tc.failing {
+				failingRoute.ServeHTTP(w, req)
+			} else {
+				route.ServeHTTP(w, req)
+			}
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"), "Unexpected content type")
+			assert.NotContains(t, w.Body.String(), "pq:", "The database error leaked")
+
+			var responseMap ProblemTestData
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Equal(t, tc.wantStatus, responseMap.Status, "Unexpected problem status")
+			assert.Equal(t, tc.wantCode, responseMap.Code, "Unexpected problem code")
+			assert.Equal(t, "urn:mytheresa:problem:"+tc.wantCode, responseMap.Type, "Unexpected problem type")
+
+			// the id sent by the client is kept when it is valid, otherwise one is generated
+			assert.NotEmpty(t, responseMap.CorrelationID, "Missing correlation id")
+			assert.Equal(t, w.Header().Get("X-Correlation-ID"), responseMap.CorrelationID, "The correlation id header and body differ")
+			if tc.wantCorrelationID != "" {
+				assert.Equal(t, tc.wantCorrelationID, responseMap.CorrelationID, "Unexpected correlation id")
+			}
+
+			var fields []string
+			for _, fe := range responseMap.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.wantFields, fields, "Unexpected invalid fields")
+		})
+	}
+}
+
+func TestHandler_ProductCRUD(t *testing.T) {
+	// the steps run in order against the same product and leave the catalogue as they found it
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantPrice  int
+	}{
+		{name: "create product", method: http.MethodPost, path: "/api/products/100001", body: `{"name":"Kanye leather boots","category":"boots","price":50000}`, wantStatus: http.StatusCreated, wantPrice: 35000},
+		{name: "create duplicated sku", method: http.MethodPost, path: "/api/products/100001", body: `{"name":"Kanye leather boots","category":"boots","price":50000}`, wantStatus: http.StatusConflict},
+		{name: "create with invalid body", method: http.MethodPost, path: "/api/products/100002", body: `{"name":"","price":-1}`, wantStatus: http.StatusBadRequest},
+		{name: "create in unknown category", method: http.MethodPost, path: "/api/products/100002", body: `{"name":"Kanye","category":"hats","price":100}`, wantStatus: http.StatusBadRequest},
+		{name: "patch price", method: http.MethodPatch, path: "/api/products/100001", body: `{"price":10000}`, wantStatus: http.StatusOK, wantPrice: 7000},
+		{name: "replace product", method: http.MethodPut, path: "/api/products/100001", body: `{"name":"Kanye sneakers","category":"sneakers","price":20000}`, wantStatus: http.StatusOK, wantPrice: 20000},
+		{name: "replace missing product", method: http.MethodPut, path: "/api/products/999999", body: `{"name":"Kanye sneakers","category":"sneakers","price":20000}`, wantStatus: http.StatusNotFound},
+		{name: "delete product", method: http.MethodDelete, path: "/api/products/100001", wantStatus: http.StatusNoContent},
+		{name: "delete missing product", method: http.MethodDelete, path: "/api/products/100001", wantStatus: http.StatusNotFound},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+	var createdAt, updatedAt time.Time
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			if tc.wantPrice == 0 {
+				return
+			}
+
+			var responseMap SingleProductTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Equal(t, tc.wantPrice, responseMap.Data.Price.Final, "Unexpected final price")
+
+			// updated_at moves forward on every write while created_at stays the same
+			if !createdAt.IsZero() {
+				assert.True(t, createdAt.Equal(responseMap.Data.CreatedAt), "created_at should not change")
+				assert.True(t, responseMap.Data.UpdatedAt.After(updatedAt), "updated_at should move forward")
+			}
+			createdAt, updatedAt = responseMap.Data.CreatedAt, responseMap.Data.UpdatedAt
+		})
+	}
+}
+
+func TestHandler_CategoryManagement(t *testing.T) {
+	// the steps run in order and leave the catalogue as they found it
+	testCases := []struct {
+		name             string
+		method           string
+		path             string
+		body             string
+		wantStatus       int
+		wantDescription  string
+		wantProductCount int
+	}{
+		{name: "create category", method: http.MethodPost, path: "/api/categories", body: `{"name":"loafers","description":"Slip-on shoes"}`, wantStatus: http.StatusCreated, wantDescription: "Slip-on shoes"},
+		{name: "create duplicated category", method: http.MethodPost, path: "/api/categories", body: `{"name":"loafers"}`, wantStatus: http.StatusConflict},
+		{name: "edit description", method: http.MethodPatch, path: "/api/categories/loafers", body: `{"description":"Leather slip-on shoes"}`, wantStatus: http.StatusOK, wantDescription: "Leather slip-on shoes"},
+		{name: "add product to category", method: http.MethodPost, path: "/api/products/200001", body: `{"name":"Horsebit loafers","category":"loafers","price":65000}`, wantStatus: http.StatusCreated},
+		{name: "delete category with products", method: http.MethodDelete, path: "/api/categories/loafers", wantStatus: http.StatusConflict},
+		{name: "merge into itself", method: http.MethodPost, path: "/api/categories/loafers/merge", body: `{"into":"loafers"}`, wantStatus: http.StatusBadRequest},
+		{name: "merge into missing category", method: http.MethodPost, path: "/api/categories/loafers/merge", body: `{"into":"hats"}`, wantStatus: http.StatusNotFound},
+		{name: "merge into sneakers", method: http.MethodPost, path: "/api/categories/loafers/merge", body: `{"into":"sneakers"}`, wantStatus: http.StatusOK, wantDescription: "Low and high-top sneakers for everyday wear", wantProductCount: 2},
+		{name: "merged category is gone", method: http.MethodGet, path: "/api/categories/loafers", wantStatus: http.StatusNotFound},
+		{name: "clean up product", method: http.MethodDelete, path: "/api/products/200001", wantStatus: http.StatusNoContent},
+		{name: "create empty category", method: http.MethodPost, path: "/api/categories", body: `{"name":"mules"}`, wantStatus: http.StatusCreated},
+		{name: "delete empty category", method: http.MethodDelete, path: "/api/categories/mules", wantStatus: http.StatusNoContent},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			route.ServeHTTP(w, req)
+			if !assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String()) {
+				t.FailNow()
+			}
+			if tc.wantStatus != http.StatusOK || !strings.HasPrefix(tc.path, "/api/categories") {
+				return
+			}
+
+			var responseMap SingleCategoryTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Equal(t, tc.wantDescription, responseMap.Data.Description, "Unexpected description")
+			assert.Equal(t, tc.wantProductCount, responseMap.Data.Products, "Unexpected number of products")
+		})
+	}
+}
+
+func TestHandler_FetchProduct(t *testing.T) {
+	testCases := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantFinal    int
+		wantDiscount null.String
+		wantCategory bool
+	}{
+		{name: "product with the highest discount applied", path: "/api/products/000003", wantStatus: http.StatusOK, wantFinal: 49700, wantDiscount: null.StringFrom("30%")},
+		{name: "product without discount", path: "/api/products/000005", wantStatus: http.StatusOK, wantFinal: 59000},
+		{name: "expand the category", path: "/api/products/000004?include=category", wantStatus: http.StatusOK, wantFinal: 79500, wantCategory: true},
+		{name: "unknown expansion", path: "/api/products/000004?include=brand", wantStatus: http.StatusBadRequest},
+		{name: "missing product", path: "/api/products/999999", wantStatus: http.StatusNotFound},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var responseMap SingleProductTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Equal(t, tc.wantFinal, responseMap.Data.Price.Final, "Unexpected final price")
+			assert.Equal(t, tc.wantDiscount, responseMap.Data.Price.DiscountPercentage, "Unexpected discount percentage")
+			if tc.wantCategory {
+				if assert.NotNil(t, responseMap.Data.CategoryDetails, "Expected the category details") {
+					assert.Equal(t, responseMap.Data.Category, responseMap.Data.CategoryDetails.Name, "Unexpected category")
+				}
+			} else {
+				assert.Nil(t, responseMap.Data.CategoryDetails, "Category details should only be set on request")
+			}
+		})
+	}
+}
+
+func TestHandler_BatchGetProducts(t *testing.T) {
+	testCases := []struct {
+		name         string
+		path         string
+		body         string
+		wantStatus   int
+		wantSKUs     []string
+		wantFinal    []int
+		wantNotFound []string
+	}{
+		{
+			name: "products keep the requested order", path: "/api/products:batchGet", body: `{"skus":["000005","999999","000003","000001","000003"]}`,
+			wantStatus: http.StatusOK, wantSKUs: []string{"000005", "000003", "000001"}, wantFinal: []int{59000, 49700, 62299}, wantNotFound: []string{"999999"},
+		},
+		{name: "empty sku list", path: "/api/products:batchGet", body: `{"skus":[]}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown action", path: "/api/products:batchDelete", body: `{"skus":["000001"]}`, wantStatus: http.StatusNotFound},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var responseMap BatchGetTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+
+			var skus []string
+			var finalPrices []int
+			for _, prod := range responseMap.Data.Products {
+				skus = append(skus, prod.SKU)
+				finalPrices = append(finalPrices, prod.Price.Final)
+			}
+			assert.Equal(t, tc.wantSKUs, skus, "Unexpected product order")
+			assert.Equal(t, tc.wantFinal, finalPrices, "Unexpected final prices")
+			assert.Equal(t, tc.wantNotFound, responseMap.Data.NotFound, "Unexpected not found skus")
+		})
+	}
+}
+
+// waitForImport polls the import until it is finished
+func waitForImport(t *testing.T, route *gin.Engine, id int) models.ImportJob {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/imports/%d", id), nil)
+		route.ServeHTTP(w, req)
+
+		var responseMap ImportTestData
+		if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+			t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+		}
+		if responseMap.Data.FinishedAt != nil {
+			return responseMap.Data
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("import %d did not finish, last state: %+v", id, responseMap.Data)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestHandler_ImportProducts(t *testing.T) {
+	// the steps run in order and the last ones remove the imported products
+	testCases := []struct {
+		name            string
+		method          string
+		path            string
+		contentType     string
+		body            string
+		wantStatus      int
+		wantJobStatus   string
+		wantCreated     int
+		wantUpdated     int
+		wantFailedLines []int
+	}{
+		{
+			name: "best effort csv keeps the valid rows", method: http.MethodPost, path: "/api/imports?mode=best_effort", contentType: "text/csv",
+			body:       "sku,name,category,price\n300001,Imported boots,boots,10000\n300002,Imported hat,hats,10000\n300003,Imported sandals,sandals,abc\n",
+			wantStatus: http.StatusAccepted, wantJobStatus: "succeeded", wantCreated: 1, wantFailedLines: []int{3, 4},
+		},
+		{
+			name: "atomic ndjson with an invalid row imports nothing", method: http.MethodPost, path: "/api/imports", contentType: "application/x-ndjson",
+			body:       `{"sku":"300001","name":"Renamed boots","category":"boots","price":12000}` + "\n" + `{"sku":"300004","name":"","category":"boots","price":12000}` + "\n",
+			wantStatus: http.StatusAccepted, wantJobStatus: "failed", wantFailedLines: []int{2},
+		},
+		{
+			name: "atomic ndjson upserts by sku", method: http.MethodPost, path: "/api/imports?mode=atomic", contentType: "application/x-ndjson",
+			body:       `{"sku":"300001","name":"Renamed boots","category":"boots","price":12000}` + "\n\n" + `{"sku":"300004","name":"Imported sneakers","category":"sneakers","price":12000}` + "\n",
+			wantStatus: http.StatusAccepted, wantJobStatus: "succeeded", wantCreated: 1, wantUpdated: 1,
+		},
+		{name: "csv without the price column", method: http.MethodPost, path: "/api/imports", contentType: "text/csv", body: "sku,name,category\n300005,Boots,boots\n", wantStatus: http.StatusBadRequest},
+		{name: "unsupported format", method: http.MethodPost, path: "/api/imports", contentType: "application/xml", body: "<products/>", wantStatus: http.StatusBadRequest},
+		{name: "missing import", method: http.MethodGet, path: "/api/imports/999999", wantStatus: http.StatusNotFound},
+		{name: "clean up first product", method: http.MethodDelete, path: "/api/products/300001", wantStatus: http.StatusNoContent},
+		{name: "clean up second product", method: http.MethodDelete, path: "/api/products/300004", wantStatus: http.StatusNoContent},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			route.ServeHTTP(w, req)
+			if !assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code, response body: %s", w.Body.String()) {
+				t.FailNow()
+			}
+			if tc.wantStatus != http.StatusAccepted {
+				return
+			}
+
+			var responseMap ImportTestData
+			err := json.Unmarshal(w.Body.Bytes(), &responseMap)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Equal(t, fmt.Sprintf("/api/imports/%d", responseMap.Data.ID), w.Header().Get("Location"), "Unexpected location")
+
+			job := waitForImport(t, route, responseMap.Data.ID)
+			var failedLines []int
+			for _, rowErr := range job.Errors {
+				failedLines = append(failedLines, rowErr.Line)
+			}
+			assert.Equal(t, tc.wantJobStatus, job.Status, "Unexpected import status")
+			assert.Equal(t, tc.wantCreated, job.CreatedRows, "Unexpected created rows")
+			assert.Equal(t, tc.wantUpdated, job.UpdatedRows, "Unexpected updated rows")
+			assert.Equal(t, tc.wantFailedLines, failedLines, "Unexpected failed lines")
+			assert.Equal(t, job.TotalRows, job.ProcessedRows, "Every row should be processed")
+		})
+	}
+}
+
+func TestHandler_ExportProducts(t *testing.T) {
+	testCases := []struct {
+		name           string
+		queryParam     string
+		acceptEncoding string
+		wantStatus     int
+		wantType       string
+		wantGzip       bool
+	}{
+		{name: "csv export", queryParam: "", wantStatus: http.StatusOK, wantType: "text/csv; charset=utf-8"},
+		{name: "gzipped csv export", queryParam: "?format=csv", acceptEncoding: "gzip, deflate", wantStatus: http.StatusOK, wantType: "text/csv; charset=utf-8", wantGzip: true},
+		{name: "gzip refused", queryParam: "?format=csv", acceptEncoding: "gzip;q=0", wantStatus: http.StatusOK, wantType: "text/csv; charset=utf-8"},
+		{name: "identity preferred", queryParam: "?format=csv", acceptEncoding: "identity, gzip;q=0.5", wantStatus: http.StatusOK, wantType: "text/csv; charset=utf-8"},
+		{name: "any coding", queryParam: "?format=ndjson", acceptEncoding: "*", wantStatus: http.StatusOK, wantType: "application/x-ndjson", wantGzip: true},
+		{name: "ndjson export", queryParam: "?format=ndjson", wantStatus: http.StatusOK, wantType: "application/x-ndjson"},
+		{name: "unknown format", queryParam: "?format=xml", wantStatus: http.StatusBadRequest},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/api/exports/products%s", tc.queryParam), nil)
+			req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.wantType, w.Header().Get("Content-Type"), "Unexpected content type")
+
+			var body io.Reader = w.Body
+			if !tc.wantGzip {
+				assert.Empty(t, w.Header().Get("Content-Encoding"), "Expected a plain body")
+			} else {
+				assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"), "Expected a gzipped body")
+				gz, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("failed to read gzipped body: %v", err)
+				}
+				body = gz
+			}
+
+			finalPrices := map[string]int{}
+			if strings.HasPrefix(tc.wantType, "text/csv") {
+				records, err := csv.NewReader(body).ReadAll()
+				if err != nil {
+					t.Fatalf("failed to read csv: %v", err)
+				}
+				assert.Equal(t, []string{"sku", "name", "category", "original_price", "final_price", "discount_percentage", "currency", "created_at", "updated_at"}, records[0], "Unexpected column order")
+				for _, record := range records[1:] {
+					finalPrice, _ := strconv.Atoi(record[4])
+					finalPrices[record[0]] = finalPrice
+				}
+			} else {
+				decoder := json.NewDecoder(body)
+				for decoder.More() {
+					var prod models.Product
+					if err := decoder.Decode(&prod); err != nil {
+						t.Fatalf("failed to decode ndjson line: %v", err)
+					}
+					finalPrices[prod.SKU] = prod.Price.Final
+				}
+			}
+			assert.Equal(t, map[string]int{"000001": 62299, "000002": 69300, "000003": 49700, "000004": 79500, "000005": 59000}, finalPrices, "Unexpected exported prices")
+		})
+	}
+}
+
+// failingExportService fails the export and the feed before the first page
+type failingExportService struct {
+	services.ProductEnsurer
+}
+
+func (failingExportService) EachProductPage(context.Context, int, func(models.Products) error) error {
+	return errors.New("connection refused")
+}
+
+func TestHandler_ExportProductsFailure(t *testing.T) {
+	route := setRouter(NewRegisteredHandler(failingExportService{}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/exports/products", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	route.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "Unexpected status code")
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"), "Expected a problem instead of the file")
+	assert.Empty(t, w.Header().Get("Content-Disposition"), "Expected no attachment")
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "Expected a plain body")
+	assert.Equal(t, []string{"Accept-Language", "Accept", "Accept-Encoding"}, w.Header().Values("Vary"), "Expected every Vary value")
+}
+
+func TestHandler_FetchProductFeedFailure(t *testing.T) {
+	route := setRouter(NewRegisteredHandler(failingExportService{}))
+
+	for _, format := range []string{"xml", "tsv"} {
+		t.Run(format, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/feeds/products?format="+format, nil)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusInternalServerError, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"), "Expected a problem instead of the feed")
+			assert.Empty(t, w.Header().Get("Content-Disposition"), "Expected no feed file")
+		})
+	}
+}
+
+func TestHandler_FetchProductFeed(t *testing.T) {
+	testCases := []struct {
+		name       string
+		queryParam string
+		wantStatus int
+		wantType   string
+	}{
+		{name: "xml feed", queryParam: "", wantStatus: http.StatusOK, wantType: "application/xml; charset=utf-8"},
+		{name: "tsv feed", queryParam: "?format=tsv", wantStatus: http.StatusOK, wantType: "text/tab-separated-values; charset=utf-8"},
+		{name: "unknown format", queryParam: "?format=csv", wantStatus: http.StatusBadRequest},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/api/feeds/products%s", tc.queryParam), nil)
+			route.ServeHTTP(w, req)
+			assert.Equal(t, tc.wantStatus, w.Code, "Unexpected status code")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.wantType, w.Header().Get("Content-Type"), "Unexpected content type")
+
+			salePrices := map[string]string{}
+			if strings.HasPrefix(tc.wantType, "text/tab-separated-values") {
+				reader := csv.NewReader(w.Body)
+				reader.Comma = '\t'
+				records, err := reader.ReadAll()
+				if err != nil {
+					t.Fatalf("failed to read tsv: %v", err)
+				}
+				assert.Equal(t, []string{"id", "title", "price", "sale_price", "sale_price_effective_date", "product_type"}, records[0], "Unexpected column order")
+				for _, record := range records[1:] {
+					salePrices[record[0]] = record[3]
+				}
+			} else {
+				var rss struct {
+					Items []struct {
+						ID        string `xml:"http://base.google.com/ns/1.0 id"`
+						SalePrice string `xml:"http://base.google.com/ns/1.0 sale_price"`
+					} `xml:"channel>item"`
+				}
+				if err := xml.Unmarshal(w.Body.Bytes(), &rss); err != nil {
+					t.Fatalf("failed to decode xml feed: %v", err)
+				}
+				for _, item := range rss.Items {
+					salePrices[item.ID] = item.SalePrice
+				}
+			}
+			assert.Equal(t, map[string]string{"000001": "622.99 EUR", "000002": "693.00 EUR", "000003": "497.00 EUR", "000004": "", "000005": ""}, salePrices, "Unexpected sale prices")
+		})
+	}
+}
+
+func TestHandler_GraphQLProducts(t *testing.T) {
+	const query = `query($first: Int, $filter: ProductFilter) {
+		products(first: $first, filter: $filter, orderBy: {field: SKU}) {
+			totalCount
+			pageInfo { hasNextPage }
+			edges { node { sku finalPrice discount } }
+		}
+	}`
+
+	testCases := []struct {
+		name          string
+		variables     string
+		wantSKUs      []string
+		wantFinal     []int
+		wantTotal     int
+		wantNextPage  bool
+		wantErrorPart string
+	}{
+		{
+			name: "filter by category", variables: `{"first": 2, "filter": {"categories": ["boots"]}}`,
+			wantSKUs: []string{"000001", "000002"}, wantFinal: []int{62299, 69300}, wantTotal: 3, wantNextPage: true,
+		},
+		{
+			name: "same filters as the rest listing", variables: `{"filter": {"priceLessThan": 80000, "minDiscount": 30}}`,
+			wantSKUs: []string{"000003"}, wantFinal: []int{49700}, wantTotal: 1,
+		},
+		{name: "page over the max page size", variables: `{"first": 1000}`, wantErrorPart: "first: must be between 0 and 100"},
+		{name: "invalid discount", variables: `{"filter": {"minDiscount": 101}}`, wantErrorPart: "minDiscount: must be between 0 and 100"},
+		{name: "negative price", variables: `{"filter": {"priceLessThan": -1}}`, wantErrorPart: "priceLessThan: must be at least 0"},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service, WithGraphQL(db)))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			body, _ := json.Marshal(map[string]any{"query": query, "variables": json.RawMessage(tc.variables)})
+			req, _ := http.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+			var responseMap GraphQLProductsTestData
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			if tc.wantErrorPart != "" {
+				if assert.Len(t, responseMap.Errors, 1, "Expected a graphql error") {
+					assert.Contains(t, responseMap.Errors[0].Message, tc.wantErrorPart, "Unexpected error")
+				}
+				return
+			}
+			assert.Empty(t, responseMap.Errors, "Unexpected graphql errors")
+
+			var skus []string
+			var finalPrices []int
+			for _, edge := range responseMap.Data.Products.Edges {
+				skus = append(skus, edge.Node.SKU)
+				finalPrices = append(finalPrices, edge.Node.FinalPrice)
+			}
+			assert.Equal(t, tc.wantSKUs, skus, "Unexpected products")
+			assert.Equal(t, tc.wantFinal, finalPrices, "Unexpected final prices")
+			assert.Equal(t, tc.wantTotal, responseMap.Data.Products.TotalCount, "Unexpected total count")
+			assert.Equal(t, tc.wantNextPage, responseMap.Data.Products.PageInfo.HasNextPage, "Unexpected next page")
+		})
+	}
+}
+
+func TestHandler_GraphQLCategoryProducts(t *testing.T) {
+	t.Setenv("MAX_PAGE_SIZE", "2")
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service, WithGraphQL(db)))
+	graphql := func(t *testing.T, query string, out any) {
+		body, _ := json.Marshal(map[string]any{"query": query})
+		req, _ := http.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		route.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+		}
+	}
+
+	t.Run("default page size", func(t *testing.T) {
+		var resp struct {
+			Data struct {
+				Categories struct {
+					Edges []struct {
+						Node struct {
+							Name     string
+							Products struct {
+								PageInfo struct{ HasNextPage bool }
+								Edges    []struct{ Node struct{ SKU string } }
+							}
+						}
+					}
+				}
+			}
+			Errors []struct{ Message string }
+		}
+		graphql(t, `{ categories(first: 1, orderBy: {field: NAME}) { edges { node { name products { pageInfo { hasNextPage } edges { node { sku } } } } } } }`, &resp)
+		assert.Empty(t, resp.Errors, "Unexpected graphql errors")
+		if assert.Len(t, resp.Data.Categories.Edges, 1, "Expected a category") {
+			boots := resp.Data.Categories.Edges[0].Node
+			assert.Equal(t, "boots", boots.Name, "Unexpected category")
+			assert.Len(t, boots.Products.Edges, 2, "Expected the products capped by MAX_PAGE_SIZE")
+			assert.True(t, boots.Products.PageInfo.HasNextPage, "Expected a next page")
+		}
+	})
+
+	t.Run("page over the max page size", func(t *testing.T) {
+		var resp struct {
+			Errors []struct{ Message string }
+		}
+		graphql(t, `{ categories(first: 1) { edges { node { products(first: 3) { edges { node { sku } } } } } } }`, &resp)
+		if assert.Len(t, resp.Errors, 1, "Expected a graphql error") {
+			assert.Contains(t, resp.Errors[0].Message, "first: must be between 0 and 2", "Unexpected error")
+		}
+	})
+}
+
+func TestHandler_GraphQLComplexityLimit(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		wantRejected bool
+	}{
+		{name: "full page", query: `{ products(first: 100) { totalCount edges { node { sku name finalPrice category { name } } } } }`},
+		{name: "nested pages", query: `{ categories(first: 100) { edges { node { products(first: 100) { edges { node { sku name finalPrice } } } } } } }`, wantRejected: true},
+		{name: "nested default pages", query: `{ categories { edges { node { products { edges { node { category { products { edges { node { sku } } } } } } } } } } }`},
+		{name: "deep nesting", query: `{ categories(first: 50) { edges { node { products(first: 50) { edges { node { category { products(first: 50) { edges { node { sku } } } } } } } } } } }`, wantRejected: true},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service, WithGraphQL(db)))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]any{"query": tc.query})
+			req, _ := http.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+			var resp struct {
+				Errors []struct {
+					Message    string
+					Extensions map[string]any
+				}
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			if !tc.wantRejected {
+				assert.Empty(t, resp.Errors, "Unexpected graphql errors")
+			} else if assert.Len(t, resp.Errors, 1, "Expected a graphql error") {
+				assert.Contains(t, resp.Errors[0].Message, "exceeds the limit of 5000", "Unexpected error")
+				assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", resp.Errors[0].Extensions["code"], "Unexpected error code")
+			}
+		})
+	}
+}
+
+func TestHandler_GraphQLFormattedPrice(t *testing.T) {
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service, WithGraphQL(db)))
+
+	body, _ := json.Marshal(map[string]any{"query": `{ products(filter: {skus: ["000003"]}) { edges { node { formatted { final } } } } }`})
+	req, _ := http.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "de")
+	w := httptest.NewRecorder()
+	route.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+	var resp struct {
+		Data struct {
+			Products struct {
+				Edges []struct {
+					Node struct {
+						Formatted models.FormattedPrice
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+	}
+	if assert.Len(t, resp.Data.Products.Edges, 1, "Expected a product") {
+		assert.Equal(t, "497,00\u00a0€", resp.Data.Products.Edges[0].Node.Formatted.Final, "Unexpected formatted price")
+	}
+}
+
+func TestHandler_GraphQLTranslations(t *testing.T) {
+	testCases := []struct {
+		name            string
+		path            string
+		acceptLanguage  string
+		wantName        string
+		wantDescription string
+	}{
+		{name: "accept language", path: "/api/graphql", acceptLanguage: "de", wantName: "Naima verzierte Wildledersandalen", wantDescription: "Flache Sandalen, Sandalen mit Absatz und Pantoletten für wärmere Tage"},
+		{name: "locale parameter wins", path: "/api/graphql?locale=fr", acceptLanguage: "de", wantName: "Sandales en daim ornées Naima", wantDescription: "Sandales plates, à talons et mules pour les beaux jours"},
+		{name: "missing translation", path: "/api/graphql?locale=it", wantName: "Naima embellished suede sandals", wantDescription: "Flat, heeled and slide sandals for the warmer days"},
+	}
+
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service, WithGraphQL(db)))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]any{"query": `{ products(filter: {skus: ["000004"]}) { edges { node { name category { description } } } } }`})
+			req, _ := http.NewRequest(http.MethodPost, tc.path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+			w := httptest.NewRecorder()
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+			var resp struct {
+				Data struct {
+					Products struct {
+						Edges []struct {
+							Node struct {
+								Name     string
+								Category struct{ Description string }
+							}
+						}
+					}
+				}
+				Errors []struct{ Message string }
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			assert.Empty(t, resp.Errors, "Unexpected graphql errors")
+			if assert.Len(t, resp.Data.Products.Edges, 1, "Expected a product") {
+				node := resp.Data.Products.Edges[0].Node
+				assert.Equal(t, tc.wantName, node.Name, "Unexpected name")
+				assert.Equal(t, tc.wantDescription, node.Category.Description, "Unexpected category description")
+			}
+		})
+	}
+}
+
+func TestHandler_GraphQLNode(t *testing.T) {
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	route := setRouter(NewRegisteredHandler(service, WithGraphQL(db)))
+	graphql := func(t *testing.T, query string, variables map[string]any, out any) {
+		body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+		req, _ := http.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		route.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+		}
+	}
+
+	var listed struct {
+		Data struct {
+			Products struct {
+				Edges []struct {
+					Node struct {
+						ID       string
+						SKU      string
+						Category struct {
+							ID   string
+							Name string
+						}
+					}
+				}
+			}
+		}
+	}
+	graphql(t, `{ products(first: 1, orderBy: {field: SKU}) { edges { node { id sku category { id name } } } } }`, nil, &listed)
+	if !assert.Len(t, listed.Data.Products.Edges, 1, "Expected a product") {
+		return
+	}
+	listedProduct := listed.Data.Products.Edges[0].Node
+	assert.NotEqual(t, listedProduct.ID, listedProduct.Category.ID, "Expected the type in the node ids")
+
+	t.Run("nodes of both types", func(t *testing.T) {
+		var resp struct {
+			Data struct {
+				Nodes []struct {
+					Typename string `json:"__typename"`
+					SKU      string
+					Name     string
+				}
+			}
+			Errors []struct{ Message string }
+		}
+		graphql(t, `query($ids: [ID!]!) { nodes(ids: $ids) { __typename ... on Product { sku } ... on Category { name } } }`,
+			map[string]any{"ids": []string{listedProduct.ID, listedProduct.Category.ID}}, &resp)
+		assert.Empty(t, resp.Errors, "Unexpected graphql errors")
+		if assert.Len(t, resp.Data.Nodes, 2, "Expected both nodes") {
+			assert.Equal(t, "Product", resp.Data.Nodes[0].Typename, "Unexpected node type")
+			assert.Equal(t, listedProduct.SKU, resp.Data.Nodes[0].SKU, "Unexpected product")
+			assert.Equal(t, "Category", resp.Data.Nodes[1].Typename, "Unexpected node type")
+			assert.Equal(t, listedProduct.Category.Name, resp.Data.Nodes[1].Name, "Unexpected category")
+		}
+	})
+
+	t.Run("same database id in both tables", func(t *testing.T) {
+		var resp struct {
+			Data struct {
+				Category struct {
+					Typename string `json:"__typename"`
+					Name     string
+				}
+				Product struct {
+					Typename string `json:"__typename"`
+					SKU      string
+				}
+			}
+			Errors []struct{ Message string }
+		}
+		// the first category and the first product are both stored with the id 1
+		graphql(t, `query($category: ID!, $product: ID!) {
+			category: node(id: $category) { __typename ... on Category { name } }
+			product: node(id: $product) { __typename ... on Product { sku } }
+		}`, map[string]any{
+			"category": graph.GlobalID{Type: "Category", ID: 1}.String(),
+			"product":  graph.GlobalID{Type: "Product", ID: 1}.String(),
+		}, &resp)
+		assert.Empty(t, resp.Errors, "Unexpected graphql errors")
+		assert.Equal(t, "Category", resp.Data.Category.Typename, "Unexpected node type")
+		assert.Equal(t, "boots", resp.Data.Category.Name, "Unexpected category")
+		assert.Equal(t, "Product", resp.Data.Product.Typename, "Unexpected node type")
+		assert.Equal(t, "000001", resp.Data.Product.SKU, "Unexpected product")
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		var resp struct {
+			Data struct {
+				Nodes []*struct{ ID string }
+			}
+			Errors []struct {
+				Message string
+				Path    []any
+			}
+		}
+		// base64 of Product:999999
+		graphql(t, `query($ids: [ID!]!) { nodes(ids: $ids) { id } }`,
+			map[string]any{"ids": []string{listedProduct.ID, "UHJvZHVjdDo5OTk5OTk="}}, &resp)
+		if assert.Len(t, resp.Data.Nodes, 2, "Expected a node per id") {
+			assert.Equal(t, listedProduct.ID, resp.Data.Nodes[0].ID, "Unexpected node")
+			assert.Nil(t, resp.Data.Nodes[1], "Expected no node for the unknown id")
+		}
+		if assert.Len(t, resp.Errors, 1, "Expected a graphql error") {
+			assert.Equal(t, []any{"nodes", float64(1)}, resp.Errors[0].Path, "Unexpected error path")
+		}
+	})
+
+	t.Run("too many ids", func(t *testing.T) {
+		var resp struct {
+			Errors []struct{ Message string }
+		}
+		ids := make([]string, 101)
+		for i := range ids {
+			ids[i] = listedProduct.ID
+		}
+		graphql(t, `query($ids: [ID!]!) { nodes(ids: $ids) { id } }`, map[string]any{"ids": ids}, &resp)
+		if assert.Len(t, resp.Errors, 1, "Expected a graphql error") {
+			assert.Contains(t, resp.Errors[0].Message, "ids: must have at most 100 ids", "Unexpected error")
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		var resp struct {
+			Errors []struct{ Message string }
+		}
+		graphql(t, `{ node(id: "MTI=") { id } }`, nil, &resp)
+		if assert.Len(t, resp.Errors, 1, "Expected a graphql error") {
+			assert.Contains(t, resp.Errors[0].Message, "invalid id", "Unexpected error")
+		}
+	})
+}
+
+func TestHandler_GRPCProducts(t *testing.T) {
+	service, err := services.NewRestService(services.WithCustomDB(db, nil))
+	if err != nil {
+		t.Fatalf("Error setting up new rest server: %v", err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := rpc.NewServer(service, 100)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial the grpc server: %v", err)
+	}
+	defer conn.Close()
+	client := productpb.NewProductServiceClient(conn)
+	ctx := context.Background()
+
+	t.Run("list products like the rest listing", func(t *testing.T) {
+		resp, err := client.ListProducts(ctx, &productpb.ListProductsRequest{Categories: []string{"boots"}, Limit: 2})
+		if err != nil {
+			t.Fatalf("ListProducts failed: %v", err)
+		}
+		var finalPrices []int64
+		for _, prod := range resp.GetProducts() {
+			finalPrices = append(finalPrices, prod.GetPrice().GetFinal())
+		}
+		assert.Equal(t, []int64{62299, 69300}, finalPrices, "Unexpected final prices")
+		assert.Equal(t, int64(3), resp.GetTotalRecords(), "Unexpected total records")
+		assert.Equal(t, int32(2), resp.GetTotalPages(), "Unexpected total pages")
+	})
+
+	t.Run("get a product with its category", func(t *testing.T) {
+		prod, err := client.GetProduct(ctx, &productpb.GetProductRequest{Sku: "000003", IncludeCategory: true})
+		if err != nil {
+			t.Fatalf("GetProduct failed: %v", err)
+		}
+		assert.Equal(t, int64(49700), prod.GetPrice().GetFinal(), "Unexpected final price")
+		assert.Equal(t, "30%", prod.GetPrice().GetDiscountPercentage(), "Unexpected discount")
+		assert.Equal(t, "boots", prod.GetCategoryDetails().GetName(), "Unexpected category")
+	})
+
+	t.Run("get a product for a locale", func(t *testing.T) {
+		prod, err := client.GetProduct(ctx, &productpb.GetProductRequest{Sku: "000003", Locale: "de-AT"})
+		if err != nil {
+			t.Fatalf("GetProduct failed: %v", err)
+		}
+		assert.Equal(t, "497,00\u00a0€", prod.GetPrice().GetFormatted().GetFinal(), "Unexpected formatted price")
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		_, err := client.GetProduct(ctx, &productpb.GetProductRequest{Sku: "999999"})
+		assert.Equal(t, codes.NotFound, status.Code(err), "Unexpected status code")
+	})
+
+	t.Run("batch get keeps the requested order", func(t *testing.T) {
+		resp, err := client.BatchGetProducts(ctx, &productpb.BatchGetProductsRequest{Skus: []string{"000005", "999999", "000001"}})
+		if err != nil {
+			t.Fatalf("BatchGetProducts failed: %v", err)
+		}
+		var skus []string
+		for _, prod := range resp.GetProducts() {
+			skus = append(skus, prod.GetSku())
+		}
+		assert.Equal(t, []string{"000005", "000001"}, skus, "Unexpected product order")
+		assert.Equal(t, []string{"999999"}, resp.GetNotFound(), "Unexpected not found skus")
+	})
+
+	t.Run("invalid request lists the fields", func(t *testing.T) {
+		_, err := client.ListProducts(ctx, &productpb.ListProductsRequest{Limit: 101, MinDiscount: 120, Locale: "not a tag"})
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code(), "Unexpected status code")
+
+		var fields []string
+		for _, detail := range st.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.GetFieldViolations() {
+					fields = append(fields, violation.GetField())
+				}
+			}
+		}
+		assert.Equal(t, []string{"limit", "min_discount", "locale"}, fields, "Unexpected invalid fields")
+	})
+}
+
+// panickingService fails every call with a nil pointer panic
+type panickingService struct {
+	services.ProductEnsurer
+}
+
+func TestHandler_GRPCRecoversPanics(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := rpc.NewServer(panickingService{}, 100)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial the grpc server: %v", err)
+	}
+	defer conn.Close()
+	client := productpb.NewProductServiceClient(conn)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetProduct(context.Background(), &productpb.GetProductRequest{Sku: "000001"})
+		assert.Equal(t, codes.Internal, status.Code(err), "Expected the panic as an internal error")
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxImportBytes caps the size of an uploaded catalogue file
+const maxImportBytes = 10 << 20
+
+// importFormats maps the accepted upload content types to their import format
+var importFormats = map[string]string{
+	"text/csv":             models.ImportFormatCSV,
+	"application/x-ndjson": models.ImportFormatNDJSON,
+	"application/ndjson":   models.ImportFormatNDJSON,
+}
+
+// ImportProducts starts a background import of the CSV or NDJSON body, the progress is reported by FetchImport
+func (h *Handler) ImportProducts(c *gin.Context) {
+	params := newQueryBinder(c, "mode", "format")
+	mode := params.String("mode", false, maxSearchLength)
+	format := params.String("format", false, maxSearchLength)
+
+	switch mode {
+	case "":
+		mode = models.ImportModeAtomic
+	case models.ImportModeAtomic, models.ImportModeBestEffort:
+	default:
+		params.fail("mode", fmt.Sprintf("must be one of %s, %s", models.ImportModeAtomic, models.ImportModeBestEffort))
+	}
+
+	// the format defaults to the one of the Content-Type header
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+		format = importFormats[mediaType]
+	}
+	if format != models.ImportFormatCSV && format != models.ImportFormatNDJSON {
+		params.fail("format", "must be one of csv, ndjson or sent as a text/csv or application/x-ndjson body")
+	}
+
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	resp, err := h.rs.ImportProducts(c, format, mode, body)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/imports/%d", config.BasePath(c), resp.ID))
+	config.JSON(c, "accepted", http.StatusAccepted, resp)
+}
+
+// FetchImport reports the progress and the row errors of the import in the path
+func (h *Handler) FetchImport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, models.ValidationErrors{{Field: "id", Message: "must be a whole number"}})
+		return
+	}
+
+	resp, err := h.rs.FetchImport(c, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/openapi"
+	"net/http"
+)
+
+// OpenAPI serves the OpenAPI document of the api
+func (h *Handler) OpenAPI(c *gin.Context) {
+	doc, err := openapi.JSON()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", doc)
+}
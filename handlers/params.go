@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// queryBinder reads the query parameters of a request and collects every invalid one
+// instead of stopping at the first, so a single 400 response can list them all
+type queryBinder struct {
+	c       *gin.Context
+	allowed []string
+	errs    models.ValidationErrors
+}
+
+// newQueryBinder rejects any query parameter that is not in allowed, ?locale= is read by the config.Localization
+// middleware so every route takes it
+func newQueryBinder(c *gin.Context, allowed ...string) *queryBinder {
+	b := &queryBinder{c: c, allowed: allowed}
+
+	var unknown []string
+	for key := range c.Request.URL.Query() {
+		if key != config.LocaleParam && !slices.Contains(allowed, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		b.fail(key, "unknown parameter")
+	}
+	return b
+}
+
+func (b *queryBinder) fail(field, message string) {
+	b.errs = append(b.errs, models.FieldError{Field: field, Message: message})
+}
+
+// check adds the errors of a validation run on the values read so far, every error stays in the order of the
+// allowed parameters as if the binder had found it
+func (b *queryBinder) check(err error) {
+	var errs models.ValidationErrors
+	if !errors.As(err, &errs) {
+		return
+	}
+	b.errs = append(b.errs, errs...)
+	slices.SortStableFunc(b.errs, func(a, z models.FieldError) int {
+		return slices.Index(b.allowed, a.Field) - slices.Index(b.allowed, z.Field)
+	})
+}
+
+// Whole reads a whole number and leaves its range to the caller
+func (b *queryBinder) Whole(key string, fallback int) int {
+	return b.Int(key, fallback, math.MinInt, 0)
+}
+
+// Int reads a whole number within [min, max], max <= 0 means there is no upper bound
+func (b *queryBinder) Int(key string, fallback, min, max int) int {
+	raw := strings.TrimSpace(b.c.Query(key))
+	if raw == "" {
+		return fallback
+	}
+
+	value, err := strconv.Atoi(raw)
+	switch {
+	case err != nil:
+		b.fail(key, "must be a whole number")
+	case value < min:
+		b.fail(key, fmt.Sprintf("must be at least %d", min))
+	case max > 0 && value > max:
+		b.fail(key, fmt.Sprintf("must be at most %d", max))
+	default:
+		return value
+	}
+	return fallback
+}
+
+// Bool reads a boolean such as true, false, 1 or 0
+func (b *queryBinder) Bool(key string) bool {
+	raw := strings.TrimSpace(b.c.Query(key))
+	if raw == "" {
+		return false
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		b.fail(key, "must be true or false")
+		return false
+	}
+	return value
+}
+
+// String reads a trimmed string of at most maxLen characters, maxLen <= 0 means there is no limit
+func (b *queryBinder) String(key string, required bool, maxLen int) string {
+	value := strings.TrimSpace(b.c.Query(key))
+	switch {
+	case value == "" && required:
+		b.fail(key, "is required")
+	case maxLen > 0 && len([]rune(value)) > maxLen:
+		b.fail(key, fmt.Sprintf("must be at most %d characters", maxLen))
+		return ""
+	}
+	return value
+}
+
+// List collects the values of a query parameter given either repeated (?sku=1&sku=2)
+// or comma separated (?sku=1,2), ignoring empty values. maxItems <= 0 means there is no limit
+func (b *queryBinder) List(key string, maxItems int) []string {
+	var values []string
+	for _, param := range b.c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	if maxItems > 0 && len(values) > maxItems {
+		b.fail(key, fmt.Sprintf("must have at most %d values", maxItems))
+		return nil
+	}
+	return values
+}
+
+// Include reads the ?include= list of related resources to expand, each one has to be in allowed
+func (b *queryBinder) Include(allowed ...string) []string {
+	values := b.List("include", len(allowed))
+	for _, value := range values {
+		if !slices.Contains(allowed, value) {
+			b.fail("include", fmt.Sprintf("must be one of %s", strings.Join(allowed, ", ")))
+			return nil
+		}
+	}
+	return values
+}
+
+// Err returns the validation errors collected so far, if any
+func (b *queryBinder) Err() error {
+	if len(b.errs) == 0 {
+		return nil
+	}
+	return b.errs
+}
+
+func init() {
+	// report the json name of an invalid field instead of its Go name
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+		// the keys of the translations
+		_ = v.RegisterValidation("locale", func(fl validator.FieldLevel) bool {
+			return config.SupportedLocale(fl.Field().String())
+		})
+	}
+}
+
+// bindJSON decodes the request body into v and turns every binding rule that failed into a validation error
+func bindJSON(c *gin.Context, v any) error {
+	err := c.ShouldBindJSON(v)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return models.ValidationErrors{{Field: "body", Message: "must be a valid JSON object"}}
+	}
+
+	errs := make(models.ValidationErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		errs = append(errs, models.FieldError{Field: fe.Field(), Message: ruleMessage(fe)})
+	}
+	return errs
+}
+
+// ruleMessage describes a failed binding rule the same way queryBinder does
+func ruleMessage(fe validator.FieldError) string {
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", fe.Param())
+	case "http_url":
+		return "must be an http or https url"
+	case "locale":
+		return fmt.Sprintf("%s is not a supported locale", fe.Value())
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}
+
+// pathSKU reads the sku from the path and makes sure it fits in the products table
+func pathSKU(c *gin.Context) (string, error) {
+	sku := strings.TrimSpace(c.Param("sku"))
+	if sku == "" || len(sku) > maxSKULength {
+		return "", models.ValidationErrors{{Field: "sku", Message: fmt.Sprintf("must be between 1 and %d characters", maxSKULength)}}
+	}
+	return sku, nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/openapi"
+)
+
+// NewRouter serves the api of the handler under /api, validation tells which requests and responses are checked
+// against the OpenAPI document
+func NewRouter(h *Handler, validation openapi.Options) *gin.Engine {
+	router := gin.New()
+	// NegotiateVersion runs these again for the v2 twin of a route, the first pass already logged the request
+	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{Skip: config.Rerouted}), gin.Recovery())
+	router.Use(config.CorrelationID())
+	apiGroupRoute := router.Group("/api")
+	apiGroupRoute.Use(config.NegotiateVersion(router))
+	// after the negotiation, which serves the v2 twin through the whole chain again
+	apiGroupRoute.Use(config.Localization())
+	apiGroupRoute.Use(openapi.Validator(validation))
+	apiGroupRoute.GET("/openapi.json", h.OpenAPI)
+
+	// the v1 routes keep the original envelope, v2 serves the same resources with the data + meta + links envelope.
+	// A v1 route is served by its v2 twin when the Accept header asks for application/vnd.mytheresa.v2+json
+	v1 := apiGroupRoute.Group("", config.Version(config.V1))
+	resourceRoutes(v1, h)
+	v1.GET("/exports/products", h.ExportProducts)
+	v1.GET("/feeds/products", h.FetchProductFeed)
+	v1.GET("/products/stream", h.StreamPriceChanges)
+	v1.POST("/webhooks", h.CreateWebhook)
+	v1.GET("/webhooks", h.FetchWebhooks)
+	v1.GET("/webhooks/dead-letters", h.FetchDeadLetters)
+	v1.GET("/webhooks/:id", h.FetchWebhook)
+	v1.DELETE("/webhooks/:id", h.DeleteWebhook)
+	v1.GET("/webhooks/:id/deliveries", h.FetchWebhookDeliveries)
+	v1.POST("/webhooks/:id/deliveries/:delivery/redeliver", h.RedeliverWebhook)
+	v1.GET("/graphql", h.GraphQL)
+	v1.POST("/graphql", h.GraphQL)
+	v1.GET("/graphql/playground", h.GraphQLPlayground)
+	v1.GET("/", h.Test)
+
+	v2 := apiGroupRoute.Group("/v2", config.Version(config.V2))
+	resourceRoutes(v2, h)
+	return router
+}
+
+// resourceRoutes registers the JSON resources every api version serves
+func resourceRoutes(group *gin.RouterGroup, h *Handler) {
+	group.GET("/products", h.FetchProducts)
+	group.GET("/products/suggest", h.SuggestProducts)
+	group.GET("/products/:sku", h.FetchProduct)
+	group.POST("/products/:sku", h.CreateProduct)
+	group.POST("/products:action", h.ProductAction)
+	group.PUT("/products/:sku", h.ReplaceProduct)
+	group.PATCH("/products/:sku", h.PatchProduct)
+	group.DELETE("/products/:sku", h.DeleteProduct)
+	group.POST("/imports", h.ImportProducts)
+	group.GET("/imports/:id", h.FetchImport)
+	group.GET("/categories", h.FetchCategories)
+	group.POST("/categories", h.CreateCategory)
+	group.GET("/categories/:name", h.FetchCategory)
+	group.PATCH("/categories/:name", h.PatchCategory)
+	group.DELETE("/categories/:name", h.DeleteCategory)
+	group.POST("/categories/:name/merge", h.MergeCategory)
+}
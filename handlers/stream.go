@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultStreamHeartbeat = 15
+	// lastEventIDHeader is sent by the browsers when an EventSource reconnects
+	lastEventIDHeader = "Last-Event-ID"
+)
+
+// StreamPriceChanges sends the price changes of the products as server-sent events until the client disconnects.
+// A comment is sent every STREAM_HEARTBEAT_INTERVAL seconds so the proxies keep the connection open, and a client
+// that reconnects with Last-Event-ID, or ?lastEventId= where it can't set headers, gets the changes it missed
+func (h *Handler) StreamPriceChanges(c *gin.Context) {
+	params := newQueryBinder(c, "category", "lastEventId")
+	filter := models.PriceStreamFilter{
+		Categories:  params.List("category", maxFilterValues),
+		LastEventID: params.lastEventID(),
+	}
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// the context of gin only ends with the request when the engine falls back to it
+	ctx := c.Request.Context()
+	sub, err := h.rs.SubscribePriceChanges(ctx, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// nginx would buffer the events otherwise
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+
+	locale := config.Locale(c)
+	send := func(change models.PriceChange) {
+		change.Localize(locale)
+		c.Render(-1, sse.Event{Id: strconv.FormatUint(change.ID, 10), Event: "price", Data: change})
+	}
+
+	if sub.Reset {
+		// the id moves the client past the changes it can't get anymore
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatUint(sub.LastEventID, 10),
+			Event: "reset",
+			Data:  gin.H{"detail": "the changes since Last-Event-ID are no longer available, reload the prices"},
+		})
+	}
+	for _, change := range sub.Replay {
+		send(change)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change, ok := <-sub.Events:
+			if !ok {
+				// the client fell behind or the server is shutting down, it resumes with Last-Event-ID
+				return
+			}
+			send(change)
+		case <-heartbeat.C:
+			_, _ = io.WriteString(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// lastEventID reads the id of the last event the client got, the Last-Event-ID header wins over ?lastEventId=
+func (b *queryBinder) lastEventID() uint64 {
+	key, raw := lastEventIDHeader, strings.TrimSpace(b.c.GetHeader(lastEventIDHeader))
+	if raw == "" {
+		key, raw = "lastEventId", strings.TrimSpace(b.c.Query("lastEventId"))
+	}
+	if raw == "" {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		b.fail(key, "must be the id of a price stream event")
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"net/http"
+	"strconv"
+)
+
+// CreateWebhook subscribes a partner url to catalogue events, the secret that signs the payloads is only returned here
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var input models.WebhookInput
+	if err := bindJSON(c, &input); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.CreateWebhook(c, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/webhooks/%d", config.BasePath(c), resp.ID))
+	config.JSON(c, "created", http.StatusCreated, resp)
+}
+
+// FetchWebhooks lists the subscribed webhooks
+func (h *Handler) FetchWebhooks(c *gin.Context) {
+	resp, err := h.rs.FetchWebhooks(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// FetchWebhook returns the webhook in the path
+func (h *Handler) FetchWebhook(c *gin.Context) {
+	id, err := pathID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.FetchWebhook(c, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// DeleteWebhook unsubscribes the webhook in the path and drops its delivery log
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	id, err := pathID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.rs.DeleteWebhook(c, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// FetchWebhookDeliveries returns the delivery log of the webhook in the path, newest first
+func (h *Handler) FetchWebhookDeliveries(c *gin.Context) {
+	id, err := pathID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	params := newQueryBinder(c, "status", "page", "limit")
+	filter := models.DeliveryFilter{
+		WebhookID: id,
+		Status:    params.String("status", false, maxSearchLength),
+		Page:      params.Int("page", 1, 1, maxPage),
+		Limit:     params.Int("limit", min(defaultPageSize, h.maxPageSize), 1, h.maxPageSize),
+	}
+	switch filter.Status {
+	case "", models.DeliveryStatusPending, models.DeliveryStatusSucceeded, models.DeliveryStatusDead:
+	default:
+		params.fail("status", fmt.Sprintf("must be one of %s, %s, %s",
+			models.DeliveryStatusPending, models.DeliveryStatusSucceeded, models.DeliveryStatusDead))
+	}
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.FetchWebhookDeliveries(c, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// FetchDeadLetters lists the deliveries of every webhook that ran out of attempts, newest first
+func (h *Handler) FetchDeadLetters(c *gin.Context) {
+	params := newQueryBinder(c, "page", "limit")
+	filter := models.DeliveryFilter{
+		Status: models.DeliveryStatusDead,
+		Page:   params.Int("page", 1, 1, maxPage),
+		Limit:  params.Int("limit", min(defaultPageSize, h.maxPageSize), 1, h.maxPageSize),
+	}
+	if err := params.Err(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.FetchWebhookDeliveries(c, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "successful", http.StatusOK, resp)
+}
+
+// RedeliverWebhook queues the delivery in the path again, typically one taken from the dead-letter list
+func (h *Handler) RedeliverWebhook(c *gin.Context) {
+	id, err := pathID(c, "id")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	deliveryID, err := pathID(c, "delivery")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.rs.RedeliverWebhook(c, id, deliveryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	config.JSON(c, "accepted", http.StatusAccepted, resp)
+}
+
+// pathID reads a numeric id from the path
+func pathID(c *gin.Context, key string) (int, error) {
+	id, err := strconv.Atoi(c.Param(key))
+	if err != nil {
+		return 0, models.ValidationErrors{{Field: key, Message: "must be a whole number"}}
+	}
+	return id, nil
+}
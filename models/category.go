@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type (
+	Category struct {
+		ID          int    `json:"ID,omitempty"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		// DescriptionTranslations holds the description in the other locales, Localize picks one of them
+		DescriptionTranslations map[string]string `json:"description_translations,omitempty"`
+		Products                int               `json:"products"`
+		CreatedAt               time.Time         `json:"created_at"`
+		UpdatedAt               time.Time         `json:"updated_at"`
+	}
+
+	Categories []Category
+
+	// CategoryInput is the body used to create a category
+	CategoryInput struct {
+		Name                    string            `json:"name" binding:"required,max=255"`
+		Description             string            `json:"description" binding:"max=1000"`
+		DescriptionTranslations map[string]string `json:"description_translations" binding:"omitempty,max=20,dive,keys,locale,endkeys,max=1000"`
+	}
+
+	// CategoryPatch is the body used to rename a category or edit its description, nil fields are left untouched
+	CategoryPatch struct {
+		Name        *string `json:"name" binding:"omitempty,min=1,max=255"`
+		Description *string `json:"description" binding:"omitempty,max=1000"`
+		// DescriptionTranslations replaces every translation of the description, an empty object removes them
+		DescriptionTranslations map[string]string `json:"description_translations" binding:"omitempty,max=20,dive,keys,locale,endkeys,max=1000"`
+	}
+
+	// CategoryMerge is the body used to move every product of a category into another one
+	CategoryMerge struct {
+		Into string `json:"into" binding:"required"`
+	}
+)
+
+func (cd *Category) Localize(locale string) {
+	cd.Description = Translate(cd.Description, cd.DescriptionTranslations, locale)
+}
+
+func (cds Categories) Localize(locale string) {
+	for i := range cds {
+		cds[i].Localize(locale)
+	}
+}
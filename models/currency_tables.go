@@ -0,0 +1,204 @@
+// Code generated by go run currencygen.go. DO NOT EDIT.
+
+package models
+
+// currencyPatterns is where the CLDR 47.0 currency format of a locale puts the symbol, ¤ is the symbol and # the
+// amount. The locales missing here use the pattern of their parent and then the root one, ¤ #
+var currencyPatterns = map[string]string{
+	"af":       "¤#",
+	"agq":      "#¤",
+	"ak":       "¤#",
+	"am":       "¤#",
+	"ar":       "\u200f#\u00a0¤",
+	"asa":      "#\u00a0¤",
+	"ast":      "#\u00a0¤",
+	"az":       "#\u00a0¤",
+	"az-Cyrl":  "#\u00a0¤",
+	"bas":      "#\u00a0¤",
+	"be":       "#\u00a0¤",
+	"bem":      "¤#",
+	"bez":      "#¤",
+	"bg":       "#\u00a0¤",
+	"bm":       "¤#",
+	"bn":       "#¤",
+	"bn-IN":    "¤#",
+	"br":       "#\u00a0¤",
+	"bs":       "#\u00a0¤",
+	"bs-Cyrl":  "#\u00a0¤",
+	"ca":       "#\u00a0¤",
+	"ccp":      "#¤",
+	"ce":       "#\u00a0¤",
+	"cgg":      "¤#",
+	"chr":      "¤#",
+	"ckb":      "#\u00a0¤",
+	"cs":       "#\u00a0¤",
+	"cy":       "¤#",
+	"da":       "#\u00a0¤",
+	"dav":      "¤#",
+	"de":       "#\u00a0¤",
+	"de-AT":    "¤\u00a0#",
+	"de-CH":    "¤\u00a0#",
+	"de-LI":    "¤\u00a0#",
+	"dje":      "#¤",
+	"dsb":      "#\u00a0¤",
+	"dua":      "#\u00a0¤",
+	"dyo":      "#\u00a0¤",
+	"dz":       "¤#",
+	"ebu":      "¤#",
+	"ee":       "¤#",
+	"el":       "#\u00a0¤",
+	"en":       "¤#",
+	"en-AT":    "¤\u00a0#",
+	"en-MV":    "¤\u00a0#",
+	"eo":       "#\u00a0¤",
+	"es":       "#\u00a0¤",
+	"es-419":   "¤#",
+	"es-AR":    "¤\u00a0#",
+	"es-CO":    "¤\u00a0#",
+	"es-GQ":    "¤#",
+	"es-PE":    "¤\u00a0#",
+	"es-PY":    "¤\u00a0#",
+	"es-UY":    "¤\u00a0#",
+	"et":       "#\u00a0¤",
+	"eu":       "#\u00a0¤",
+	"ewo":      "#\u00a0¤",
+	"fa":       "\u200e¤#",
+	"fa-AF":    "¤\u00a0#",
+	"ff":       "#\u00a0¤",
+	"fi":       "#\u00a0¤",
+	"fil":      "¤#",
+	"fo":       "#\u00a0¤",
+	"fr":       "#\u00a0¤",
+	"ga":       "¤#",
+	"gd":       "¤#",
+	"gl":       "#\u00a0¤",
+	"gsw":      "#\u00a0¤",
+	"gu":       "¤#",
+	"guz":      "¤#",
+	"gv":       "¤#",
+	"haw":      "¤#",
+	"he":       "\u200f#\u00a0\u200f¤",
+	"hi":       "¤#",
+	"hr":       "#\u00a0¤",
+	"hsb":      "#\u00a0¤",
+	"hu":       "#\u00a0¤",
+	"hy":       "#\u00a0¤",
+	"id":       "¤#",
+	"ig":       "¤#",
+	"is":       "#\u00a0¤",
+	"it":       "#\u00a0¤",
+	"it-CH":    "¤\u00a0#",
+	"ja":       "¤#",
+	"jmc":      "¤#",
+	"ka":       "#\u00a0¤",
+	"kab":      "#¤",
+	"kam":      "¤#",
+	"kde":      "¤#",
+	"kea":      "#\u00a0¤",
+	"khq":      "#¤",
+	"ki":       "¤#",
+	"kk":       "#\u00a0¤",
+	"kl":       "¤#",
+	"kln":      "¤#",
+	"km":       "#¤",
+	"kn":       "¤#",
+	"ko":       "¤#",
+	"ks":       "¤#",
+	"ksb":      "#¤",
+	"ksf":      "#\u00a0¤",
+	"ksh":      "#\u00a0¤",
+	"kw":       "¤#",
+	"ky":       "#\u00a0¤",
+	"lb":       "#\u00a0¤",
+	"lg":       "#¤",
+	"ln":       "#\u00a0¤",
+	"lo":       "¤#",
+	"lt":       "#\u00a0¤",
+	"lu":       "#¤",
+	"luo":      "#¤",
+	"luy":      "¤#",
+	"lv":       "#\u00a0¤",
+	"mas":      "¤#",
+	"mer":      "¤#",
+	"mk":       "#\u00a0¤",
+	"ml":       "¤#",
+	"mr":       "¤#",
+	"ms":       "¤#",
+	"ms-BN":    "¤\u00a0#",
+	"ms-ID":    "¤#",
+	"mt":       "¤#",
+	"mua":      "¤#",
+	"my":       "#\u00a0¤",
+	"naq":      "¤#",
+	"nd":       "¤#",
+	"nmg":      "#\u00a0¤",
+	"nn":       "#\u00a0¤",
+	"no":       "#\u00a0¤",
+	"nus":      "¤#",
+	"nyn":      "¤#",
+	"om":       "¤#",
+	"or":       "¤#",
+	"pa":       "¤#",
+	"pl":       "#\u00a0¤",
+	"prg":      "#\u00a0¤",
+	"pt-PT":    "#\u00a0¤",
+	"rm":       "#\u00a0¤",
+	"rn":       "#¤",
+	"ro":       "#\u00a0¤",
+	"rof":      "¤#",
+	"ru":       "#\u00a0¤",
+	"rwk":      "#¤",
+	"sah":      "#\u00a0¤",
+	"saq":      "¤#",
+	"sbp":      "#¤",
+	"sd":       "#\u00a0¤",
+	"sd-IN":    "¤\u00a0#",
+	"se":       "#\u00a0¤",
+	"seh":      "#¤",
+	"ses":      "#¤",
+	"sg":       "¤#",
+	"shi":      "#¤",
+	"shi-Latn": "#¤",
+	"si":       "¤#",
+	"sk":       "#\u00a0¤",
+	"sl":       "#\u00a0¤",
+	"smn":      "#\u00a0¤",
+	"sn":       "¤#",
+	"so":       "¤#",
+	"sq":       "#\u00a0¤",
+	"sr":       "#\u00a0¤",
+	"sr-Latn":  "#\u00a0¤",
+	"sv":       "#\u00a0¤",
+	"ta":       "¤#",
+	"ta-MY":    "¤\u00a0#",
+	"ta-SG":    "¤\u00a0#",
+	"te":       "¤#",
+	"teo":      "¤#",
+	"tg":       "#\u00a0¤",
+	"th":       "¤#",
+	"ti":       "¤#",
+	"tk":       "#\u00a0¤",
+	"tr":       "¤#",
+	"tt":       "#\u00a0¤",
+	"twq":      "#¤",
+	"tzm":      "#\u00a0¤",
+	"ug":       "¤#",
+	"uk":       "#\u00a0¤",
+	"ur":       "¤#",
+	"ur-IN":    "¤\u00a0#",
+	"uz":       "#\u00a0¤",
+	"uz-Cyrl":  "#\u00a0¤",
+	"vai":      "¤#",
+	"vai-Latn": "¤#",
+	"vi":       "#\u00a0¤",
+	"vun":      "¤#",
+	"xog":      "#\u00a0¤",
+	"yav":      "#\u00a0¤",
+	"yo":       "¤#",
+	"yue":      "¤#",
+	"yue-Hans": "¤#",
+	"zgh":      "#¤",
+	"zh":       "¤#",
+	"zh-Hant":  "¤#",
+	"zu":       "¤#",
+}
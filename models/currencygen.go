@@ -0,0 +1,118 @@
+//go:build ignore
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"log"
+	"os"
+	"os/exec"
+	"slices"
+)
+
+// rootLayout is the layout of the CLDR root locale, ICU resolves und to the default locale instead
+const rootLayout = "¤\u00a0#"
+
+// layoutScript prints, for every locale ICU has data for, where the CLDR currency format puts the symbol. ¤ is the
+// symbol and # the amount, the narrow € keeps ICU from adding the spacing of the symbols made of letters
+const layoutScript = `
+const tags = JSON.parse(require("fs").readFileSync(0, "utf8"));
+const layouts = {};
+for (const tag of tags) {
+	const nf = new Intl.NumberFormat(tag, {style: "currency", currency: "EUR", currencyDisplay: "narrowSymbol"});
+	if (nf.resolvedOptions().locale !== tag) continue;
+	let layout = "";
+	for (const part of nf.formatToParts(623)) {
+		if (part.type === "currency") layout += "¤";
+		else if (part.type === "literal") layout += part.value;
+		else if (!layout.endsWith("#")) layout += "#";
+	}
+	layouts[tag] = layout;
+}
+console.log(JSON.stringify({cldr: process.versions.cldr, layouts}));
+`
+
+// main writes currency_tables.go from the CLDR data node ships with ICU, a locale is only listed when its layout
+// differs from the one of its parent
+func main() {
+	var candidates []string
+	var regions []language.Region
+	for a := 'A'; a <= 'Z'; a++ {
+		for b := 'A'; b <= 'Z'; b++ {
+			if region, err := language.ParseRegion(string([]rune{a, b})); err == nil && region.IsCountry() {
+				regions = append(regions, region)
+			}
+		}
+	}
+	for _, tag := range display.Supported.Tags() {
+		candidates = append(candidates, tag.String())
+		base, _ := tag.Base()
+		for _, region := range regions {
+			if regional, err := language.Compose(base, region); err == nil {
+				candidates = append(candidates, regional.String())
+			}
+		}
+	}
+	slices.Sort(candidates)
+	candidates = slices.Compact(candidates)
+
+	input, err := json.Marshal(candidates)
+	if err != nil {
+		log.Fatalf("encoding the locales: %v", err)
+	}
+	cmd := exec.Command("node", "-e", layoutScript)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		log.Fatalf("running node, it needs the full ICU data: %v", err)
+	}
+	var result struct {
+		CLDR    string
+		Layouts map[string]string
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		log.Fatalf("decoding the layouts: %v", err)
+	}
+
+	inherited := func(tag language.Tag) string {
+		for tag != language.Und {
+			tag = tag.Parent()
+			if layout, ok := result.Layouts[tag.String()]; ok {
+				return layout
+			}
+		}
+		return rootLayout
+	}
+	var tags []string
+	for tag, layout := range result.Layouts {
+		if layout != inherited(language.Make(tag)) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by go run currencygen.go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package models\n\n")
+	fmt.Fprintf(&buf, "// currencyPatterns is where the CLDR %s currency format of a locale puts the symbol, ¤ is the symbol and # the\n", result.CLDR)
+	fmt.Fprintf(&buf, "// amount. The locales missing here use the pattern of their parent and then the one of the root locale\n")
+	fmt.Fprintf(&buf, "var currencyPatterns = map[string]string{\n")
+	for _, tag := range tags {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", tag, result.Layouts[tag])
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting the table: %v", err)
+	}
+	if err := os.WriteFile("currency_tables.go", src, 0o644); err != nil {
+		log.Fatalf("writing the table: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// FieldError describes why a single request field was rejected
+	FieldError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+
+	// ValidationErrors holds every invalid field of a request so the client can fix them all at once
+	ValidationErrors []FieldError
+)
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+	for _, fe := range ve {
+		messages = append(messages, fmt.Sprintf("%s: %s", fe.Field, fe.Message))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// RowError holds every invalid field of a single row of an import file
+type RowError struct {
+	Line   int              `json:"line"`
+	SKU    string           `json:"sku,omitempty"`
+	Errors ValidationErrors `json:"errors"`
+}
+
+// ErrorKind groups the errors by how the client should react to them, every kind maps to one status code
+type ErrorKind string
+
+const (
+	ErrorKindValidation    ErrorKind = "validation"
+	ErrorKindNotFound      ErrorKind = "not_found"
+	ErrorKindConflict      ErrorKind = "conflict"
+	ErrorKindTooLarge      ErrorKind = "too_large"
+	ErrorKindNotAcceptable ErrorKind = "not_acceptable"
+	ErrorKindInternal      ErrorKind = "internal"
+	ErrorKindUnavailable   ErrorKind = "unavailable"
+)
+
+// Error is an error the client can be told about. Code is a stable machine-readable identifier and Detail a message
+// that is safe to show, the wrapped Err is only logged since it may hold database messages
+type Error struct {
+	Kind   ErrorKind
+	Code   string
+	Detail string
+	Fields ValidationErrors
+	Err    error
+}
+
+// NewError returns an error of the given kind, the sentinel errors of the services are built with it
+func NewError(kind ErrorKind, code, detail string) *Error {
+	return &Error{Kind: kind, Code: code, Detail: detail}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Detail, e.Err)
+	}
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
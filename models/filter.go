@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// the limits of a product filter, the same for the REST, GraphQL and gRPC apis
+const (
+	MaxFilterValues = 50
+	MaxSearchLength = 100
+	MaxPage         = 10000
+)
+
+type (
+	// ProductFilter holds the query parameters used to narrow down the product listing
+	ProductFilter struct {
+		Categories    []string
+		SKUs          []string
+		PriceLessThan int
+		Search        string
+		OnSale        bool
+		MinDiscount   int
+		Page          int
+		Limit         int
+		// Fields are the top level json fields of Product the client asked for, every field is loaded when empty
+		Fields []string
+	}
+
+	// ProductFilterFields are the names an api gives the fields of ProductFilter, the validation errors use them.
+	// An empty Page means the api doesn't page by number, like the GraphQL connections
+	ProductFilterFields struct {
+		Categories, SKUs, PriceLessThan, Search, MinDiscount, Page, Limit string
+	}
+
+	// PriceStreamFilter picks the price changes a stream client receives, LastEventID resumes the stream after
+	// the event with this id when it isn't zero
+	PriceStreamFilter struct {
+		Categories  []string
+		LastEventID uint64
+	}
+)
+
+// Validate checks the filter with the rules every api shares, maxPageSize caps the limit. A zero PriceLessThan
+// doesn't filter by price
+func (f ProductFilter) Validate(fields ProductFilterFields, maxPageSize int) error {
+	var errs ValidationErrors
+	fail := func(field, message string) {
+		errs = append(errs, FieldError{Field: field, Message: message})
+	}
+
+	if fields.Page != "" {
+		if f.Page < 1 || f.Page > MaxPage {
+			fail(fields.Page, fmt.Sprintf("must be between 1 and %d", MaxPage))
+		}
+		if f.Limit < 1 || f.Limit > maxPageSize {
+			fail(fields.Limit, fmt.Sprintf("must be between 1 and %d", maxPageSize))
+		}
+	}
+	if len(f.Categories) > MaxFilterValues {
+		fail(fields.Categories, fmt.Sprintf("must have at most %d values", MaxFilterValues))
+	}
+	if len(f.SKUs) > MaxFilterValues {
+		fail(fields.SKUs, fmt.Sprintf("must have at most %d values", MaxFilterValues))
+	}
+	if f.PriceLessThan < 0 {
+		fail(fields.PriceLessThan, "must be at least 0")
+	}
+	if utf8.RuneCountInString(f.Search) > MaxSearchLength {
+		fail(fields.Search, fmt.Sprintf("must be at most %d characters", MaxSearchLength))
+	}
+	if f.MinDiscount < 0 || f.MinDiscount > 100 {
+		fail(fields.MinDiscount, "must be between 0 and 100")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	// ImportModeAtomic writes nothing unless every row is valid and stored
+	ImportModeAtomic = "atomic"
+	// ImportModeBestEffort stores the valid rows and reports the invalid ones
+	ImportModeBestEffort = "best_effort"
+)
+
+type (
+	// ImportJob reports the progress of a catalogue import
+	ImportJob struct {
+		ID            int        `json:"ID"`
+		Status        string     `json:"status"`
+		Mode          string     `json:"mode"`
+		Format        string     `json:"format"`
+		TotalRows     int        `json:"total_rows"`
+		ProcessedRows int        `json:"processed_rows"`
+		CreatedRows   int        `json:"created_rows"`
+		UpdatedRows   int        `json:"updated_rows"`
+		FailedRows    int        `json:"failed_rows"`
+		Errors        []RowError `json:"errors"`
+		CreatedAt     time.Time  `json:"created_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+		FinishedAt    *time.Time `json:"finished_at"`
+	}
+)
@@ -8,3 +8,30 @@ type (
 		Limit        int `json:"limit"`
 	}
 )
+
+// Paginated is implemented by the responses that hold a single page of a listing,
+// the v2 envelope moves the meta out of the data next to the pagination links
+type Paginated interface {
+	PageItems() any
+	PageMeta() Meta
+}
+
+// Tabular is implemented by the responses that can be rendered as CSV, one record per item in the order of Columns
+type Tabular interface {
+	Columns() []string
+	Records() [][]string
+}
+
+// Localizable is implemented by the responses holding translated texts, Localize replaces the texts with their
+// translation in the locale and keeps the untranslated ones when there is none
+type Localizable interface {
+	Localize(locale string)
+}
+
+// Translate returns the translation of text in the locale, text itself when there is none
+func Translate(text string, translations map[string]string, locale string) string {
+	if translation, ok := translations[locale]; ok {
+		return translation
+	}
+	return text
+}
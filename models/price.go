@@ -0,0 +1,99 @@
+package models
+
+import (
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:generate go run currencygen.go
+
+// nbsp separates the symbol from the amount in the CLDR currency formats
+const nbsp = "\u00a0"
+
+// rootCurrencyPattern is the currency format of the CLDR root locale
+const rootCurrencyPattern = "¤" + nbsp + "#"
+
+// FormatPrice renders an amount in the minor unit of the currency, e.g. cents, the way the locale displays prices:
+// 62300 EUR is €623.00 in en and 623,00 € in de
+func FormatPrice(amount int, currencyCode, locale string) string {
+	tag := language.Make(locale)
+	printer := message.NewPrinter(tag)
+
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		return printer.Sprint(number.Decimal(amount)) + nbsp + currencyCode
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	value := printer.Sprint(number.Decimal(float64(amount)/math.Pow10(scale), number.Scale(scale)))
+	symbol := printer.Sprint(currency.Symbol(unit))
+
+	// x/text formats the amount and the symbol with the CLDR data of the locale, the generated patterns place them
+	pattern := rootCurrencyPattern
+	for t := tag; ; t = t.Parent() {
+		if p, ok := currencyPatterns[t.String()]; ok {
+			pattern = p
+			break
+		}
+		if t == language.Und {
+			break
+		}
+	}
+	// CLDR keeps a symbol made of letters such as CHF apart from the amount
+	if first, _ := utf8.DecodeRuneInString(symbol); unicode.IsLetter(first) {
+		pattern = strings.Replace(pattern, "#¤", "#"+nbsp+"¤", 1)
+	}
+	if last, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(last) {
+		pattern = strings.Replace(pattern, "¤#", "¤"+nbsp+"#", 1)
+	}
+	return strings.NewReplacer("¤", symbol, "#", value).Replace(pattern)
+}
+
+// Localize formats the original and final amounts for the locale
+func (pd *PriceData) Localize(locale string) {
+	pd.Formatted = &FormattedPrice{
+		Original: FormatPrice(pd.Original, pd.Currency, locale),
+		Final:    FormatPrice(pd.Final, pd.Currency, locale),
+	}
+}
+
+// the reasons of a PriceChange
+const (
+	// PriceChangeUpdate is a product or a category that was edited or imported
+	PriceChangeUpdate = "update"
+	// PriceChangePromotion is a promotion that started or ended
+	PriceChangePromotion = "promotion"
+)
+
+// PriceChange is an event of the price stream, Previous and Current are the prices of the product before and
+// after the change. PreviousCategory is only set when the product moved to another category, and ID only in the
+// price stream
+type PriceChange struct {
+	ID               uint64    `json:"id,omitempty"`
+	SKU              string    `json:"sku"`
+	Category         string    `json:"category"`
+	PreviousCategory string    `json:"previous_category,omitempty"`
+	Reason           string    `json:"reason"`
+	Previous         PriceData `json:"previous"`
+	Current          PriceData `json:"current"`
+	ChangedAt        time.Time `json:"changed_at"`
+}
+
+func (pc *PriceChange) Localize(locale string) {
+	pc.Previous.Localize(locale)
+	pc.Current.Localize(locale)
+}
+
+// InCategory reports whether the product was in one of the categories before or after the change, every product
+// is when there are none
+func (pc *PriceChange) InCategory(categories []string) bool {
+	return len(categories) == 0 || slices.Contains(categories, pc.Category) ||
+		(pc.PreviousCategory != "" && slices.Contains(categories, pc.PreviousCategory))
+}
@@ -2,25 +2,49 @@ package models
 
 import (
 	"github.com/guregu/null/v5"
+	"strconv"
 	"time"
 )
 
+// ProductColumns is the column order of the products rendered as CSV, append new columns at the end so consumers
+// don't break
+var ProductColumns = []string{
+	"sku", "name", "category", "original_price", "final_price", "discount_percentage", "currency", "created_at", "updated_at",
+}
+
 type (
+	// Product is the priced product returned by the api, CategoryDetails is only set
+	// when the category is expanded with ?include=category
 	Product struct {
-		ID        int       `json:"ID,omitempty"`
-		SKU       string    `json:"sku"`
-		Name      string    `json:"name"`
-		Category  string    `json:"category"`
-		Price     PriceData `json:"price"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+		ID   int    `json:"ID,omitempty"`
+		SKU  string `json:"sku"`
+		Name string `json:"name"`
+		// NameTranslations holds the name in the other locales, Localize picks one of them
+		NameTranslations map[string]string `json:"name_translations,omitempty"`
+		Category         string            `json:"category"`
+		Price            PriceData         `json:"price"`
+		Highlight        null.String       `json:"highlight,omitempty"`
+		CategoryDetails  *Category         `json:"category_details,omitempty"`
+		CreatedAt        time.Time         `json:"created_at"`
+		UpdatedAt        time.Time         `json:"updated_at"`
 	}
 
 	PriceData struct {
 		Original           int         `json:"original"`
 		Final              int         `json:"final"`
 		DiscountPercentage null.String `json:"discount_percentage,omitempty"`
+		DiscountStartsAt   null.Time   `json:"discount_starts_at,omitempty"`
+		DiscountEndsAt     null.Time   `json:"discount_ends_at,omitempty"`
 		Currency           string      `json:"currency"`
+		// Formatted is only set on the responses that know the locale of the client
+		Formatted *FormattedPrice `json:"formatted,omitempty"`
+	}
+
+	// FormattedPrice holds the amounts of PriceData ready to display, formatted with the CLDR rules of the locale
+	// and the currency, e.g. €623.00 in en and 623,00 € in de
+	FormattedPrice struct {
+		Original string `json:"original"`
+		Final    string `json:"final"`
 	}
 
 	ProductsResponse struct {
@@ -29,4 +53,95 @@ type (
 	}
 
 	Products []Product
+
+	// BatchGetRequest is the body used to look up many products at once
+	BatchGetRequest struct {
+		SKUs []string `json:"skus" binding:"required,min=1,max=200,dive,required,max=64"`
+	}
+
+	// BatchGetResponse holds the found products in the requested order and the skus that don't exist
+	BatchGetResponse struct {
+		Products Products `json:"products"`
+		NotFound []string `json:"not_found"`
+	}
+
+	// ProductInput is the body used to create or replace a product
+	ProductInput struct {
+		Name             string            `json:"name" binding:"required,max=255"`
+		NameTranslations map[string]string `json:"name_translations" binding:"omitempty,max=20,dive,keys,locale,endkeys,required,max=255"`
+		Category         string            `json:"category" binding:"required"`
+		Price            int               `json:"price" binding:"required,min=1"`
+	}
+
+	// ProductPatch is the body used to partially update a product, nil fields are left untouched
+	ProductPatch struct {
+		Name *string `json:"name" binding:"omitempty,min=1,max=255"`
+		// NameTranslations replaces every translation of the name, an empty object removes them
+		NameTranslations map[string]string `json:"name_translations" binding:"omitempty,max=20,dive,keys,locale,endkeys,required,max=255"`
+		Category         *string           `json:"category" binding:"omitempty,min=1"`
+		Price            *int              `json:"price" binding:"omitempty,min=1"`
+	}
 )
+
+func (pr *ProductsResponse) PageItems() any {
+	if pr.Products == nil {
+		return []Product{}
+	}
+	return pr.Products
+}
+
+func (pr *ProductsResponse) PageMeta() Meta {
+	return pr.Meta
+}
+
+func (pr *ProductsResponse) Localize(locale string) {
+	Products(pr.Products).Localize(locale)
+}
+
+func (br *BatchGetResponse) Localize(locale string) {
+	br.Products.Localize(locale)
+}
+
+func (ps Products) Localize(locale string) {
+	for i := range ps {
+		ps[i].Localize(locale)
+	}
+}
+
+func (pd *Product) Localize(locale string) {
+	pd.Name = Translate(pd.Name, pd.NameTranslations, locale)
+	pd.Price.Localize(locale)
+	if pd.CategoryDetails != nil {
+		// the details may be shared with a cached product
+		cd := *pd.CategoryDetails
+		cd.Localize(locale)
+		pd.CategoryDetails = &cd
+	}
+}
+
+func (pr *ProductsResponse) Columns() []string {
+	return ProductColumns
+}
+
+func (pr *ProductsResponse) Records() [][]string {
+	records := make([][]string, 0, len(pr.Products))
+	for _, pd := range pr.Products {
+		records = append(records, pd.Record())
+	}
+	return records
+}
+
+// Record renders the product in the order of ProductColumns
+func (pd Product) Record() []string {
+	return []string{
+		pd.SKU,
+		pd.Name,
+		pd.Category,
+		strconv.Itoa(pd.Price.Original),
+		strconv.Itoa(pd.Price.Final),
+		pd.Price.DiscountPercentage.String,
+		pd.Price.Currency,
+		pd.CreatedAt.UTC().Format(time.RFC3339),
+		pd.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
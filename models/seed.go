@@ -6,12 +6,15 @@ type SeedData struct {
 }
 
 type CategorySeed struct {
-	Name string `json:"name"`
+	Name                    string            `json:"name"`
+	Description             string            `json:"description"`
+	DescriptionTranslations map[string]string `json:"description_translations"`
 }
 
 type ProductSeed struct {
-	SKU      string `json:"sku"`
-	Name     string `json:"name"`
-	Category string `json:"category"`
-	Price    int    `json:"price"`
+	SKU              string            `json:"sku"`
+	Name             string            `json:"name"`
+	NameTranslations map[string]string `json:"name_translations"`
+	Category         string            `json:"category"`
+	Price            int               `json:"price"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/guregu/null/v5"
+
+const (
+	SuggestionTypeProduct  = "product"
+	SuggestionTypeCategory = "category"
+)
+
+type (
+	// Suggestion is a single autocomplete entry, the SKU is only set for products
+	Suggestion struct {
+		Type  string      `json:"type"`
+		Text  string      `json:"text"`
+		SKU   null.String `json:"sku,omitempty"`
+		Score float64     `json:"score"`
+	}
+
+	Suggestions []Suggestion
+)
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// the catalogue events a webhook can subscribe to
+const (
+	// WebhookProductCreated is sent with the priced Product when a new sku is created or imported
+	WebhookProductCreated = "product.created"
+	// WebhookProductRepriced is sent with the PriceChange whenever the original price, the final price or the
+	// discount of a product changes
+	WebhookProductRepriced = "product.repriced"
+	// WebhookProductOnSale is sent with the PriceChange when a product gets a discount it didn't have
+	WebhookProductOnSale = "product.on_sale"
+)
+
+const (
+	// DeliveryStatusPending is a delivery waiting for its first attempt or for a retry
+	DeliveryStatusPending = "pending"
+	// DeliveryStatusSucceeded is a delivery the receiver answered with a 2xx status
+	DeliveryStatusSucceeded = "succeeded"
+	// DeliveryStatusDead is a delivery that ran out of attempts, it stays in the dead-letter list until it is redelivered
+	DeliveryStatusDead = "dead"
+)
+
+type (
+	// WebhookInput is the body used to subscribe a webhook
+	WebhookInput struct {
+		URL    string   `json:"url" binding:"required,http_url,max=2048"`
+		Events []string `json:"events" binding:"required,min=1,max=3,dive,oneof=product.created product.repriced product.on_sale"`
+		// Secret signs the payloads, a random one is generated when it is empty
+		Secret string `json:"secret" binding:"omitempty,min=16,max=255"`
+	}
+
+	// Webhook is a partner endpoint subscribed to catalogue events
+	Webhook struct {
+		ID     int      `json:"ID"`
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+		// Secret is only returned when the webhook is created
+		Secret    string    `json:"secret,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	Webhooks []Webhook
+
+	// WebhookEvent is the body of a delivery, Data is a Product or a PriceChange depending on Type. The id is the
+	// same for every webhook and attempt of an event so receivers can drop the duplicates
+	WebhookEvent struct {
+		ID        string    `json:"id"`
+		Type      string    `json:"type"`
+		CreatedAt time.Time `json:"created_at"`
+		Data      any       `json:"data"`
+	}
+
+	// DeliveryAttempt is the outcome of a request sent to a webhook, StatusCode is zero when no response came back
+	DeliveryAttempt struct {
+		At         time.Time `json:"at"`
+		StatusCode int       `json:"status_code,omitempty"`
+		Error      string    `json:"error,omitempty"`
+		DurationMS int64     `json:"duration_ms"`
+	}
+
+	// WebhookDelivery is an event sent or to be sent to a webhook with the log of its attempts
+	WebhookDelivery struct {
+		ID            int               `json:"ID"`
+		WebhookID     int               `json:"webhook_id"`
+		EventID       string            `json:"event_id"`
+		Event         string            `json:"event"`
+		Status        string            `json:"status"`
+		Attempts      []DeliveryAttempt `json:"attempts"`
+		AttemptsLeft  int               `json:"attempts_left"`
+		NextAttemptAt *time.Time        `json:"next_attempt_at"`
+		DeliveredAt   *time.Time        `json:"delivered_at"`
+		Payload       json.RawMessage   `json:"payload"`
+		CreatedAt     time.Time         `json:"created_at"`
+		UpdatedAt     time.Time         `json:"updated_at"`
+	}
+
+	// WebhookDeliveries is a page of the delivery log, newest first
+	WebhookDeliveries struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+		Meta       Meta              `json:"meta"`
+	}
+
+	// DeliveryFilter narrows down the delivery log, a zero WebhookID lists the deliveries of every webhook
+	DeliveryFilter struct {
+		WebhookID int
+		Status    string
+		Page      int
+		Limit     int
+	}
+)
+
+func (wd *WebhookDeliveries) PageItems() any {
+	if wd.Deliveries == nil {
+		return []WebhookDelivery{}
+	}
+	return wd.Deliveries
+}
+
+func (wd *WebhookDeliveries) PageMeta() Meta {
+	return wd.Meta
+}
@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+// maxRequestBytes caps the bodies read whole to be validated, before the handlers apply their own limit. It is the
+// largest body a handler accepts, the catalogue import
+const maxRequestBytes = 10 << 20
+
+// Load parses the OpenAPI document of the api, it is only parsed once
+var Load = sync.OnceValues(func() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the openapi document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+	return doc, nil
+})
+
+// JSON renders the OpenAPI document as JSON
+var JSON = sync.OnceValues(func() ([]byte, error) {
+	doc, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return doc.MarshalJSON()
+})
+
+func init() {
+	// the streamed formats are validated as plain strings
+	for _, contentType := range []string{"application/x-ndjson", "application/ndjson", "application/xml", "text/tab-separated-values", "text/html", "text/csv", "application/msgpack", "text/event-stream"} {
+		openapi3filter.RegisterBodyDecoder(contentType, func(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+			data, err := io.ReadAll(body)
+			return string(data), err
+		})
+	}
+}
+
+// Options picks what the Validator middleware checks
+type Options struct {
+	// Requests rejects the requests that don't match the document with a 400
+	Requests bool
+	// Responses replaces the responses that don't match the document with a 500, the response is buffered
+	// until it is checked so this is meant for tests. A streamed response, one the handler flushes, only has its
+	// status and headers checked on the first flush and is written through from then on
+	Responses bool
+}
+
+// Validator checks the requests and responses of the routes in the OpenAPI document,
+// the routes missing from the document are left to the router
+func Validator(opts Options) gin.HandlerFunc {
+	if !opts.Requests && !opts.Responses {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	doc, err := Load()
+	if err != nil {
+		panic(err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		panic(fmt.Errorf("failed to build the openapi router: %w", err))
+	}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+		input := requestInput(c.Request, route, pathParams)
+
+		if opts.Requests {
+			if c.Request.Body != nil {
+				c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBytes)
+			}
+			err := openapi3filter.ValidateRequest(c, input)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				config.Problem(c, &models.Error{
+					Kind:   models.ErrorKindTooLarge,
+					Code:   "file_too_large",
+					Detail: fmt.Sprintf("the body must be at most %d bytes", maxBytesErr.Limit),
+					Err:    err,
+				})
+				c.Abort()
+				return
+			}
+			if err != nil {
+				config.InvalidRequest(c, "the request doesn't match the openapi document", fieldErrors(err))
+				c.Abort()
+				return
+			}
+		}
+		if !opts.Responses {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		writer.stream = func() bool {
+			err := validateResponse(c, input, nil, true)
+			if err != nil {
+				c.Writer = writer.ResponseWriter
+				invalidResponse(c, err)
+				c.Writer = writer
+			}
+			return err == nil
+		}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+		if writer.streaming {
+			return
+		}
+
+		if err := validateResponse(c, input, writer.body.Bytes(), false); err != nil {
+			invalidResponse(c, err)
+			return
+		}
+		_, _ = c.Writer.Write(writer.body.Bytes())
+	}
+}
+
+// invalidResponse replaces the response that doesn't match the document with a problem
+func invalidResponse(c *gin.Context, err error) {
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Encoding")
+	c.Writer.Header().Del("Content-Disposition")
+	config.Problem(c, &models.Error{
+		Kind:   models.ErrorKindInternal,
+		Code:   "invalid_response",
+		Detail: "the response doesn't match the openapi document",
+		Err:    err,
+	})
+}
+
+func requestInput(req *http.Request, route *routers.Route, pathParams map[string]string) *openapi3filter.RequestValidationInput {
+	return &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			MultiError:         true,
+		},
+	}
+}
+
+// validateResponse checks the status, the headers and unless headersOnly the body of the response
+func validateResponse(c *gin.Context, input *openapi3filter.RequestValidationInput, body []byte, headersOnly bool) error {
+	header := c.Writer.Header()
+	respInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 c.Writer.Status(),
+		Header:                 header,
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+			// a compressed body can't be decoded, only its status and headers are checked
+			ExcludeResponseBody: headersOnly || header.Get("Content-Encoding") != "",
+		},
+	}
+	respInput.SetBodyBytes(body)
+	return openapi3filter.ValidateResponse(c, respInput)
+}
+
+// fieldErrors lists the invalid parameters and body fields the same way the handlers do
+func fieldErrors(err error) models.ValidationErrors {
+	var errs models.ValidationErrors
+	var walk func(field string, err error)
+	walk = func(field string, err error) {
+		// the errors wrap each other, so they are matched by type instead of errors.As which would skip a level
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, inner := range e {
+				walk(field, inner)
+			}
+		case *openapi3filter.RequestError:
+			switch {
+			case e.Parameter != nil:
+				field = e.Parameter.Name
+			case e.RequestBody != nil:
+				field = "body"
+			}
+			if e.Err == nil {
+				errs = append(errs, models.FieldError{Field: field, Message: e.Reason})
+				return
+			}
+			walk(field, e.Err)
+		case *openapi3.SchemaError:
+			if pointer := e.JSONPointer(); len(pointer) > 0 && field == "body" {
+				field = strings.Join(pointer, ".")
+			}
+			errs = append(errs, models.FieldError{Field: field, Message: e.Reason})
+		case *openapi3filter.ParseError:
+			errs = append(errs, models.FieldError{Field: field, Message: e.Error()})
+		default:
+			errs = append(errs, models.FieldError{Field: field, Message: err.Error()})
+		}
+	}
+	walk("request", err)
+	return errs
+}
+
+// errInvalidStream is returned to a handler writing a streamed response that was replaced by a problem
+var errInvalidStream = errors.New("the streamed response doesn't match the openapi document")
+
+// bufferedWriter holds the response until it is validated. The first Flush turns it into a stream: stream checks
+// the status and headers, and the response is then written through, or dropped when it was invalid
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+
+	stream    func() bool
+	streaming bool
+	invalid   bool
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.invalid:
+		return 0, errInvalidStream
+	case w.streaming:
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow keeps the status until the response is validated, a problem may replace it
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.streaming && !w.invalid {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *bufferedWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		if w.invalid = !w.stream(); w.invalid {
+			return
+		}
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if !w.invalid {
+		w.ResponseWriter.Flush()
+	}
+}
@@ -1,14 +1,29 @@
 package routes
 
 import (
+	"expvar"
 	"github.com/gin-gonic/gin"
+	"github.com/tonymj76/mytheresa-test/config"
 	"github.com/tonymj76/mytheresa-test/handlers"
+	"github.com/tonymj76/mytheresa-test/openapi"
+	"net/http"
 )
 
+// SetDebugRouter serves the expvar metrics such as the product cache hits and misses. It listens on its own port so
+// the metrics are never exposed with the api
+func SetDebugRouter() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// SetRouter serves the api of the handler, the routes are registered by handlers.NewRouter so the handler tests run
+// against the same ones
 func SetRouter(h *handlers.Handler) *gin.Engine {
-	router := gin.Default()
-	apiGroupRoute := router.Group("/api")
-	apiGroupRoute.GET("/products", h.FetchProducts)
-	apiGroupRoute.GET("/", h.Test)
-	return router
+	// OPENAPI_VALIDATION=true rejects the requests that don't match the OpenAPI document,
+	// in gin test mode the responses are checked against it too
+	return handlers.NewRouter(h, openapi.Options{
+		Requests:  config.GetEnv("OPENAPI_VALIDATION", "") == "true",
+		Responses: gin.Mode() == gin.TestMode,
+	})
 }
@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tonymj76/mytheresa-test/handlers"
+	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/openapi"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var (
+	ginParam     = regexp.MustCompile(`:(\w+)`)
+	customMethod = regexp.MustCompile(`([^/]):\w+$`)
+)
+
+// TestSetRouter_MatchesOpenAPI checks that every api route has an operation in the OpenAPI document and the other way
+// round, the custom methods like /products:batchGet are served by a single /products:action route
+func TestSetRouter_MatchesOpenAPI(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("failed to load the openapi document: %v", err)
+	}
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		path = customMethod.ReplaceAllString(path, "$1{action}")
+		for method := range item.Operations() {
+			documented = append(documented, fmt.Sprintf("%s %s", method, path))
+		}
+	}
+
+	var routed []string
+	for _, route := range SetRouter(handlers.NewRegisteredHandler(nil)).Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		path := ginParam.ReplaceAllString(strings.TrimPrefix(route.Path, "/api"), "{$1}")
+		routed = append(routed, fmt.Sprintf("%s %s", route.Method, path))
+	}
+
+	slices.Sort(documented)
+	slices.Sort(routed)
+	assert.Equal(t, slices.Compact(documented), routed, "The routes and the openapi document differ")
+}
+
+func TestSetRouter_ValidatesRequests(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantFields []string
+	}{
+		{name: "query parameters", method: http.MethodGet, path: "/api/products?limit=abc&minDiscount=120", wantFields: []string{"limit", "minDiscount"}},
+		{name: "missing required parameter", method: http.MethodGet, path: "/api/products/suggest", wantFields: []string{"prefix"}},
+		{name: "path parameter", method: http.MethodGet, path: "/api/products/" + strings.Repeat("1", 65), wantFields: []string{"sku"}},
+		{name: "json body", method: http.MethodPost, path: "/api/products/000001", body: `{"name":"","price":0}`, wantFields: []string{"name", "price", "category"}},
+		{name: "custom method body", method: http.MethodPost, path: "/api/products:batchGet", body: `{"skus":[]}`, wantFields: []string{"skus"}},
+	}
+
+	t.Setenv("OPENAPI_VALIDATION", "true")
+	route := SetRouter(handlers.NewRegisteredHandler(nil))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			route.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, "Unexpected status code, response body: %s", w.Body.String())
+
+			var responseMap struct {
+				Errors models.ValidationErrors
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &responseMap); err != nil {
+				t.Fatalf("failed to unmarshal response: %v, response body: %s", err, w.Body.String())
+			}
+			var fields []string
+			for _, fieldErr := range responseMap.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			assert.Equal(t, tc.wantFields, fields, "Unexpected invalid fields")
+		})
+	}
+}
+
+func TestSetRouter_ServesOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer gin.SetMode(gin.DebugMode)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	SetRouter(handlers.NewRegisteredHandler(nil)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Unexpected status code")
+	var doc struct {
+		OpenAPI string
+		Paths   map[string]any
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to unmarshal the openapi document: %v", err)
+	}
+	assert.Equal(t, "3.0.3", doc.OpenAPI, "Unexpected openapi version")
+	assert.Contains(t, doc.Paths, "/products", "The product listing is not documented")
+}
+
+func TestSetRouter_KeepsMetricsInternal(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/debug/vars", nil)
+	SetRouter(handlers.NewRegisteredHandler(nil)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "Expected the metrics off the api router")
+
+	w = httptest.NewRecorder()
+	SetDebugRouter().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "Expected the metrics on the debug router")
+
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("failed to unmarshal the metrics: %v, response body: %s", err, w.Body.String())
+	}
+	assert.Contains(t, vars, "product_cache", "Expected the product cache metrics")
+}
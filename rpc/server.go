@@ -0,0 +1,287 @@
+package rpc
+
+//go:generate buf generate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/config"
+	"github.com/tonymj76/mytheresa-test/models"
+	"github.com/tonymj76/mytheresa-test/rpc/productpb"
+	"github.com/tonymj76/mytheresa-test/services"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"runtime/debug"
+	"unicode/utf8"
+)
+
+// the same limits the REST api puts on its query parameters and bodies
+const (
+	defaultPageSize = 10
+	maxSKULength    = 64
+	maxBatchSize    = 200
+
+	// errorDomain is the domain of the ErrorInfo details, their reason is the same code the REST api returns
+	errorDomain = "mytheresa.com"
+)
+
+// filterFields are the fields of ListProductsRequest
+var filterFields = models.ProductFilterFields{
+	Categories: "categories", SKUs: "skus", PriceLessThan: "price_less_than", Search: "search", MinDiscount: "min_discount",
+	Page: "page", Limit: "limit",
+}
+
+// ProductServer implements the gRPC ProductService on top of the service layer shared with the REST api
+type ProductServer struct {
+	productpb.UnimplementedProductServiceServer
+
+	rs          services.ProductEnsurer
+	maxPageSize int
+}
+
+func NewProductServer(rs services.ProductEnsurer, maxPageSize int) *ProductServer {
+	return &ProductServer{rs: rs, maxPageSize: maxPageSize}
+}
+
+// NewServer returns a gRPC server with the ProductService registered, a panic in a handler or in the interceptors
+// of opts fails the call with an internal error instead of crashing the process
+func NewServer(rs services.ProductEnsurer, maxPageSize int, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(recoverUnary),
+		grpc.ChainStreamInterceptor(recoverStream),
+	}, opts...)
+	srv := grpc.NewServer(opts...)
+	productpb.RegisterProductServiceServer(srv, NewProductServer(rs, maxPageSize))
+	return srv
+}
+
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer recoverPanic(info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverPanic(info.FullMethod, &err)
+	return handler(srv, ss)
+}
+
+// recoverPanic turns a panic of the call into the internal error status, the stack is only logged
+func recoverPanic(method string, err *error) {
+	if r := recover(); r != nil {
+		*err = statusError(fmt.Errorf("%s panicked: %v\n%s", method, r, debug.Stack()))
+	}
+}
+
+// ListProducts filters and paginates the products with the same rules as GET /api/products
+func (s *ProductServer) ListProducts(ctx context.Context, req *productpb.ListProductsRequest) (*productpb.ListProductsResponse, error) {
+	filter := models.ProductFilter{
+		Categories:    req.GetCategories(),
+		SKUs:          req.GetSkus(),
+		PriceLessThan: int(req.GetPriceLessThan()),
+		Search:        req.GetSearch(),
+		OnSale:        req.GetOnSale(),
+		MinDiscount:   int(req.GetMinDiscount()),
+		Page:          int(req.GetPage()),
+		Limit:         int(req.GetLimit()),
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = min(defaultPageSize, s.maxPageSize)
+	}
+	var errs models.ValidationErrors
+	errors.As(filter.Validate(filterFields, s.maxPageSize), &errs)
+	locale, localeErr := requestLocale(req.GetLocale())
+	if localeErr != nil {
+		errs = append(errs, *localeErr)
+	}
+	if len(errs) > 0 {
+		return nil, statusError(errs)
+	}
+
+	resp, err := s.rs.FilterProduct(ctx, filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp.Localize(locale)
+	products := make([]*productpb.Product, 0, len(resp.Products))
+	for _, pd := range resp.Products {
+		products = append(products, productMessage(pd))
+	}
+	return &productpb.ListProductsResponse{
+		Products:     products,
+		TotalRecords: int64(resp.Meta.TotalRecords),
+		Page:         int32(resp.Meta.Page),
+		TotalPages:   int32(resp.Meta.TotalPages),
+		Limit:        int32(resp.Meta.Limit),
+	}, nil
+}
+
+// GetProduct returns a single product with its discounted price
+func (s *ProductServer) GetProduct(ctx context.Context, req *productpb.GetProductRequest) (*productpb.Product, error) {
+	var errs models.ValidationErrors
+	errors.As(validateSKU("sku", req.GetSku()), &errs)
+	locale, localeErr := requestLocale(req.GetLocale())
+	if localeErr != nil {
+		errs = append(errs, *localeErr)
+	}
+	if len(errs) > 0 {
+		return nil, statusError(errs)
+	}
+
+	pd, err := s.rs.FetchProduct(ctx, req.GetSku(), req.GetIncludeCategory())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pd.Localize(locale)
+	return productMessage(*pd), nil
+}
+
+// BatchGetProducts prices up to 200 products at once, keeping the order of the requested skus
+func (s *ProductServer) BatchGetProducts(ctx context.Context, req *productpb.BatchGetProductsRequest) (*productpb.BatchGetProductsResponse, error) {
+	var errs models.ValidationErrors
+	switch n := len(req.GetSkus()); {
+	case n == 0:
+		errs = append(errs, models.FieldError{Field: "skus", Message: "is required"})
+	case n > maxBatchSize:
+		errs = append(errs, models.FieldError{Field: "skus", Message: fmt.Sprintf("must have at most %d items", maxBatchSize)})
+	}
+	for i, sku := range req.GetSkus() {
+		var skuErrs models.ValidationErrors
+		if errors.As(validateSKU(fmt.Sprintf("skus[%d]", i), sku), &skuErrs) {
+			errs = append(errs, skuErrs...)
+		}
+	}
+	locale, localeErr := requestLocale(req.GetLocale())
+	if localeErr != nil {
+		errs = append(errs, *localeErr)
+	}
+	if len(errs) > 0 {
+		return nil, statusError(errs)
+	}
+
+	resp, err := s.rs.BatchGetProducts(ctx, req.GetSkus())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp.Localize(locale)
+	products := make([]*productpb.Product, 0, len(resp.Products))
+	for _, pd := range resp.Products {
+		products = append(products, productMessage(pd))
+	}
+	return &productpb.BatchGetProductsResponse{Products: products, NotFound: resp.NotFound}, nil
+}
+
+func validateSKU(field, sku string) error {
+	switch {
+	case sku == "":
+		return models.ValidationErrors{{Field: field, Message: "is required"}}
+	case utf8.RuneCountInString(sku) > maxSKULength:
+		return models.ValidationErrors{{Field: field, Message: fmt.Sprintf("must be at most %d characters", maxSKULength)}}
+	}
+	return nil
+}
+
+// requestLocale picks the supported locale closest to the locale field of a request
+func requestLocale(raw string) (string, *models.FieldError) {
+	if raw == "" {
+		return config.DefaultLocale(), nil
+	}
+	tag, err := language.Parse(raw)
+	if err != nil {
+		return "", &models.FieldError{Field: "locale", Message: "must be a language tag such as de or en-GB"}
+	}
+	return config.MatchLocale(tag), nil
+}
+
+// kindCodes is the gRPC code of every error kind of the service
+var kindCodes = map[models.ErrorKind]codes.Code{
+	models.ErrorKindValidation:  codes.InvalidArgument,
+	models.ErrorKindNotFound:    codes.NotFound,
+	models.ErrorKindConflict:    codes.AlreadyExists,
+	models.ErrorKindTooLarge:    codes.ResourceExhausted,
+	models.ErrorKindInternal:    codes.Internal,
+	models.ErrorKindUnavailable: codes.Unavailable,
+}
+
+// statusError maps the errors returned by the service to the matching gRPC status with the same taxonomy as the
+// REST api, the validation errors are attached as BadRequest field violations and the internal details are only logged
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "the request was canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "the request deadline was exceeded")
+	}
+
+	apiErr := services.ClassifyError(err)
+	code, ok := kindCodes[apiErr.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+	if code == codes.Internal {
+		logrus.WithError(err).WithField("code", apiErr.Code).Error("grpc request failed")
+	}
+
+	st := status.New(code, apiErr.Detail)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: apiErr.Code, Domain: errorDomain}}
+	if len(apiErr.Fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(apiErr.Fields))
+		for _, fieldErr := range apiErr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: fieldErr.Field, Description: fieldErr.Message})
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if detailed, derr := st.WithDetails(details...); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func productMessage(pd models.Product) *productpb.Product {
+	msg := &productpb.Product{
+		Id:       int64(pd.ID),
+		Sku:      pd.SKU,
+		Name:     pd.Name,
+		Category: pd.Category,
+		Price: &productpb.Price{
+			Original:           int64(pd.Price.Original),
+			Final:              int64(pd.Price.Final),
+			DiscountPercentage: pd.Price.DiscountPercentage.Ptr(),
+			Currency:           pd.Price.Currency,
+		},
+		Highlight: pd.Highlight.Ptr(),
+		CreatedAt: timestamppb.New(pd.CreatedAt),
+		UpdatedAt: timestamppb.New(pd.UpdatedAt),
+	}
+	if formatted := pd.Price.Formatted; formatted != nil {
+		msg.Price.Formatted = &productpb.FormattedPrice{Original: formatted.Original, Final: formatted.Final}
+	}
+	if pd.Price.DiscountStartsAt.Valid {
+		msg.Price.DiscountStartsAt = timestamppb.New(pd.Price.DiscountStartsAt.Time)
+	}
+	if pd.Price.DiscountEndsAt.Valid {
+		msg.Price.DiscountEndsAt = timestamppb.New(pd.Price.DiscountEndsAt.Time)
+	}
+	if cd := pd.CategoryDetails; cd != nil {
+		msg.CategoryDetails = &productpb.Category{
+			Id:          int64(cd.ID),
+			Name:        cd.Name,
+			Description: cd.Description,
+			Products:    int64(cd.Products),
+			CreatedAt:   timestamppb.New(cd.CreatedAt),
+			UpdatedAt:   timestamppb.New(cd.UpdatedAt),
+		}
+	}
+	return msg
+}
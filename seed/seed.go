@@ -37,7 +37,11 @@ func SeedDatabase(client *ent.Client, filepath string) error {
 
 		if err != nil { // Category doesn't exist; create it
 			log.Printf("Creating category: %s", cat.Name)
-			newCategory, err := client.Category.Create().SetName(cat.Name).SetDescription("best product ever").Save(ctx)
+			newCategory, err := client.Category.Create().
+				SetName(cat.Name).
+				SetDescription(cat.Description).
+				SetDescriptionTranslations(cat.DescriptionTranslations).
+				Save(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create category %s: %v", cat.Name, err)
 			}
@@ -70,6 +74,7 @@ func SeedDatabase(client *ent.Client, filepath string) error {
 			Create().
 			SetSku(prod.SKU).
 			SetName(prod.Name).
+			SetNameTranslations(prod.NameTranslations).
 			SetPrice(prod.Price).
 			SetCategory(cate).
 			Save(ctx)
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/models"
+	"slices"
+	"sync"
+	"time"
+)
+
+// cacheMetrics is published on /debug/vars, the counters are shared by every cache of the process
+var cacheMetrics = expvar.NewMap("product_cache")
+
+// productCache keeps the most recently used FilterProduct results. Any product or category mutation made through
+// the ent client and any promotion start or end drops every entry, a listing can depend on any product. The hooks
+// only see the mutations of this instance, the entries expire so the changes of the other instances show up too
+type productCache struct {
+	mu      sync.Mutex
+	entries *expirable.LRU[string, *models.ProductsResponse]
+	// generation is bumped on every invalidation, a result loaded during an invalidation is not stored
+	generation uint64
+	// promotionVersion is the version of the promotions the entries were priced with
+	promotionVersion string
+}
+
+// WithProductCache caches up to size FilterProduct results for at most ttl, a size of zero or less disables the cache.
+// It registers ent hooks on the client so it must come after the option that sets the database
+func WithProductCache(size int, ttl time.Duration) RestServiceConfiguration {
+	return func(rs *RestService) error {
+		if size <= 0 {
+			return nil
+		}
+		if rs.DB == nil {
+			return errors.New("the product cache needs a database, pass WithProductCache after the database option")
+		}
+		if ttl <= 0 {
+			return errors.New("the product cache needs a ttl above zero")
+		}
+
+		rs.productCache = &productCache{entries: expirable.NewLRU[string, *models.ProductsResponse](size, nil, ttl)}
+		rs.DB.Product.Use(rs.productCache.invalidateHook)
+		rs.DB.Category.Use(rs.productCache.invalidateHook)
+		return nil
+	}
+}
+
+// invalidateHook drops the entries once a mutation is saved, and again when its transaction commits since a
+// listing loaded in between would still see the old rows
+func (pc *productCache) invalidateHook(next ent.Mutator) ent.Mutator {
+	return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+		value, err := next.Mutate(ctx, m)
+		if err != nil {
+			return value, err
+		}
+		pc.invalidate()
+
+		if txm, ok := m.(interface{ Tx() (*ent.Tx, error) }); ok {
+			if tx, err := txm.Tx(); err == nil {
+				tx.OnCommit(func(next ent.Committer) ent.Committer {
+					return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+						err := next.Commit(ctx, tx)
+						pc.invalidate()
+						return err
+					})
+				})
+			}
+		}
+		return value, nil
+	})
+}
+
+func (pc *productCache) invalidate() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.invalidateLocked()
+}
+
+func (pc *productCache) invalidateLocked() {
+	pc.generation++
+	if pc.entries.Len() > 0 {
+		cacheMetrics.Add("invalidations", 1)
+		pc.entries.Purge()
+	}
+}
+
+// get returns the cached result of the filter or loads and stores it
+func (pc *productCache) get(ctx context.Context, filter models.ProductFilter,
+	load func(context.Context, models.ProductFilter) (*models.ProductsResponse, error)) (*models.ProductsResponse, error) {
+	key := cacheKey(filter)
+
+	pc.mu.Lock()
+	// the cached prices are stale once a promotion starts or ends
+	if version := PromotionVersion(time.Now()); version != pc.promotionVersion {
+		pc.invalidateLocked()
+		pc.promotionVersion = version
+	}
+	generation := pc.generation
+	resp, ok := pc.entries.Get(key)
+	pc.mu.Unlock()
+
+	if ok {
+		cacheMetrics.Add("hits", 1)
+		return cloneProductsResponse(resp), nil
+	}
+	cacheMetrics.Add("misses", 1)
+
+	resp, err := load(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pc.mu.Lock()
+	if generation == pc.generation {
+		if evicted := pc.entries.Add(key, cloneProductsResponse(resp)); evicted {
+			cacheMetrics.Add("evictions", 1)
+		}
+	}
+	pc.mu.Unlock()
+	return resp, nil
+}
+
+// cacheKey normalizes the filter so the requests that return the same products share an entry
+func cacheKey(filter models.ProductFilter) string {
+	categories := slices.Compact(slices.Sorted(slices.Values(filter.Categories)))
+	skus := slices.Compact(slices.Sorted(slices.Values(filter.SKUs)))
+	// FilterPredicates ignores priceLessThan when filtering by category, and a minimum discount implies on sale
+	priceLessThan := filter.PriceLessThan
+	if len(categories) > 0 {
+		priceLessThan = 0
+	}
+	onSale := filter.OnSale || filter.MinDiscount > 0
+	// the products loaded for a selection of fields miss the other columns
+	fields := slices.Compact(slices.Sorted(slices.Values(filter.Fields)))
+
+	return fmt.Sprintf("page=%d;limit=%d;category=%q;sku=%q;priceLessThan=%d;q=%q;onSale=%t;minDiscount=%d;fields=%q",
+		filter.Page, filter.Limit, categories, skus, priceLessThan, filter.Search, onSale, filter.MinDiscount, fields)
+}
+
+// cloneProductsResponse copies the products so neither the caller nor the cache sees the changes of the other
+func cloneProductsResponse(resp *models.ProductsResponse) *models.ProductsResponse {
+	clone := *resp
+	clone.Products = slices.Clone(resp.Products)
+	return &clone
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+// categoryByName looks up a category with the given client, which may belong to a transaction
+func categoryByName(ctx context.Context, client *ent.Client, name string) (*ent.Category, error) {
+	cate, err := client.Category.Query().Where(category.Name(name)).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category %s: %w", name, err)
+	}
+	return cate, nil
+}
+
+func categoryResponseFields(ecd *ent.Category, products int) models.Category {
+	cd := models.Category{
+		ID:                      ecd.ID,
+		Name:                    ecd.Name,
+		DescriptionTranslations: ecd.DescriptionTranslations,
+		Products:                products,
+		CreatedAt:               ecd.CreatedAt,
+		UpdatedAt:               ecd.UpdatedAt,
+	}
+	if ecd.Description != nil {
+		cd.Description = *ecd.Description
+	}
+	return cd
+}
+
+// categoryResponse reloads a category with the number of products assigned to it
+func (rs *RestService) categoryResponse(ctx context.Context, name string) (*models.Category, error) {
+	ecd, err := categoryByName(ctx, rs.DB, name)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := ecd.QueryProducts().Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed counting products of category %s: %w", name, err)
+	}
+
+	cd := categoryResponseFields(ecd, products)
+	return &cd, nil
+}
+
+// FetchCategories lists every category with the number of products assigned to it
+func (rs *RestService) FetchCategories(ctx context.Context) (models.Categories, error) {
+	dbCategories, err := rs.DB.Category.Query().Order(ent.Asc(category.FieldName)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	var counts []struct {
+		CategoryID int `json:"category_products"`
+		Count      int `json:"count"`
+	}
+	err = rs.DB.Product.Query().
+		Where(product.HasCategory()).
+		GroupBy(product.CategoryColumn).
+		Aggregate(ent.Count()).
+		Scan(ctx, &counts)
+	if err != nil {
+		return nil, fmt.Errorf("failed counting products per category: %w", err)
+	}
+
+	productsPerCategory := make(map[int]int, len(counts))
+	for _, count := range counts {
+		productsPerCategory[count.CategoryID] = count.Count
+	}
+
+	categories := make(models.Categories, 0, len(dbCategories))
+	for _, dbCategory := range dbCategories {
+		categories = append(categories, categoryResponseFields(dbCategory, productsPerCategory[dbCategory.ID]))
+	}
+	return categories, nil
+}
+
+// FetchCategory returns a single category
+func (rs *RestService) FetchCategory(ctx context.Context, name string) (*models.Category, error) {
+	return rs.categoryResponse(ctx, name)
+}
+
+// CreateCategory adds a new category
+func (rs *RestService) CreateCategory(ctx context.Context, input models.CategoryInput) (*models.Category, error) {
+	_, err := rs.DB.Category.Create().
+		SetName(input.Name).
+		SetDescription(input.Description).
+		SetDescriptionTranslations(input.DescriptionTranslations).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrCategoryConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category %s: %w", input.Name, err)
+	}
+
+	return rs.categoryResponse(ctx, input.Name)
+}
+
+// PatchCategory renames a category or edits its description
+func (rs *RestService) PatchCategory(ctx context.Context, name string, patch models.CategoryPatch) (*models.Category, error) {
+	ecd, err := categoryByName(ctx, rs.DB, name)
+	if err != nil {
+		return nil, err
+	}
+
+	update := ecd.Update()
+	if patch.Name != nil {
+		update.SetName(*patch.Name)
+		name = *patch.Name
+	}
+	if patch.Description != nil {
+		update.SetDescription(*patch.Description)
+	}
+	if patch.DescriptionTranslations != nil {
+		update.SetDescriptionTranslations(patch.DescriptionTranslations)
+	}
+
+	_, err = update.Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrCategoryConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update category %s: %w", ecd.Name, err)
+	}
+
+	return rs.categoryResponse(ctx, name)
+}
+
+// DeleteCategory removes a category, as long as no product is assigned to it anymore
+func (rs *RestService) DeleteCategory(ctx context.Context, name string) error {
+	// the emptiness check is part of the DELETE statement so a product added meanwhile can't be orphaned
+	deleted, err := rs.DB.Category.Delete().
+		Where(category.Name(name), category.Not(category.HasProducts())).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete category %s: %w", name, err)
+	}
+	if deleted > 0 {
+		return nil
+	}
+
+	exists, err := rs.DB.Category.Query().Where(category.Name(name)).Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch category %s: %w", name, err)
+	}
+	if exists {
+		return ErrCategoryNotEmpty
+	}
+	return ErrCategoryNotFound
+}
+
+// MergeCategory moves every product of a category into another one and deletes the emptied category,
+// both happen in a single transaction so a failure leaves the catalogue untouched
+func (rs *RestService) MergeCategory(ctx context.Context, name string, merge models.CategoryMerge) (*models.Category, error) {
+	if name == merge.Into {
+		return nil, models.ValidationErrors{{Field: "into", Message: "must be a different category"}}
+	}
+
+	err := rs.withTx(ctx, func(tx *ent.Tx) error {
+		from, err := categoryByName(ctx, tx.Client(), name)
+		if err != nil {
+			return err
+		}
+
+		into, err := categoryByName(ctx, tx.Client(), merge.Into)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Product.Update().
+			Where(product.HasCategoryWith(category.ID(from.ID))).
+			SetCategoryID(into.ID).
+			Save(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to move products from %s to %s: %w", from.Name, into.Name, err)
+		}
+
+		if err := tx.Category.DeleteOne(from).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete category %s: %w", from.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.categoryResponse(ctx, merge.Into)
+}
@@ -1,10 +1,34 @@
 package services
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
 	"github.com/tonymj76/mytheresa-test/models"
+	"io"
 )
 
 type ProductEnsurer interface {
-	FilterProduct(*gin.Context, string, int, int, int) (*models.ProductsResponse, error)
+	FilterProduct(context.Context, models.ProductFilter) (*models.ProductsResponse, error)
+	SuggestProducts(context.Context, string, int) (models.Suggestions, error)
+	EachProductPage(context.Context, int, func(models.Products) error) error
+	FetchProduct(context.Context, string, bool) (*models.Product, error)
+	BatchGetProducts(context.Context, []string) (*models.BatchGetResponse, error)
+	CreateProduct(context.Context, string, models.ProductInput) (*models.Product, error)
+	ReplaceProduct(context.Context, string, models.ProductInput) (*models.Product, error)
+	PatchProduct(context.Context, string, models.ProductPatch) (*models.Product, error)
+	DeleteProduct(context.Context, string) error
+	ImportProducts(context.Context, string, string, io.Reader) (*models.ImportJob, error)
+	FetchImport(context.Context, int) (*models.ImportJob, error)
+	FetchCategories(context.Context) (models.Categories, error)
+	FetchCategory(context.Context, string) (*models.Category, error)
+	CreateCategory(context.Context, models.CategoryInput) (*models.Category, error)
+	PatchCategory(context.Context, string, models.CategoryPatch) (*models.Category, error)
+	DeleteCategory(context.Context, string) error
+	MergeCategory(context.Context, string, models.CategoryMerge) (*models.Category, error)
+	SubscribePriceChanges(context.Context, models.PriceStreamFilter) (*PriceSubscription, error)
+	CreateWebhook(context.Context, models.WebhookInput) (*models.Webhook, error)
+	FetchWebhooks(context.Context) (models.Webhooks, error)
+	FetchWebhook(context.Context, int) (*models.Webhook, error)
+	DeleteWebhook(context.Context, int) error
+	FetchWebhookDeliveries(context.Context, models.DeliveryFilter) (*models.WebhookDeliveries, error)
+	RedeliverWebhook(context.Context, int, int) (*models.WebhookDelivery, error)
 }
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+var (
+	// ErrProductNotFound is returned when no product has the requested sku
+	ErrProductNotFound = models.NewError(models.ErrorKindNotFound, "product_not_found", "product not found")
+	// ErrSKUConflict is returned when creating a product with a sku that is already taken
+	ErrSKUConflict = models.NewError(models.ErrorKindConflict, "sku_conflict", "a product with this sku already exists")
+	// ErrCategoryNotFound is returned when no category has the requested name
+	ErrCategoryNotFound = models.NewError(models.ErrorKindNotFound, "category_not_found", "category not found")
+	// ErrCategoryConflict is returned when creating or renaming a category to a name that is already taken
+	ErrCategoryConflict = models.NewError(models.ErrorKindConflict, "category_conflict", "a category with this name already exists")
+	// ErrCategoryNotEmpty is returned when deleting a category that still has products
+	ErrCategoryNotEmpty = models.NewError(models.ErrorKindConflict, "category_not_empty", "category still has products, merge it into another category first")
+	// ErrPriceStreamDisabled is returned when subscribing to the price stream of a service started without it
+	ErrPriceStreamDisabled = models.NewError(models.ErrorKindUnavailable, "price_stream_disabled", "the price stream is disabled")
+)
+
+// ClassifyError turns any error returned by the service into a models.Error the client can be told about.
+// The ent errors that weren't mapped to a sentinel error get a generic code of their kind and everything
+// else is an internal error, the original error is kept in Err for the logs
+func ClassifyError(err error) *models.Error {
+	var apiErr *models.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var validationErrs models.ValidationErrors
+	var entValidationErr *ent.ValidationError
+	switch {
+	case errors.As(err, &validationErrs):
+		return &models.Error{Kind: models.ErrorKindValidation, Code: "invalid_request", Detail: "the request has invalid fields", Fields: validationErrs, Err: err}
+	case errors.As(err, &entValidationErr):
+		return &models.Error{
+			Kind:   models.ErrorKindValidation,
+			Code:   "invalid_request",
+			Detail: "the request has invalid fields",
+			Fields: models.ValidationErrors{{Field: entValidationErr.Name, Message: "is invalid"}},
+			Err:    err,
+		}
+	case ent.IsNotFound(err):
+		return &models.Error{Kind: models.ErrorKindNotFound, Code: "not_found", Detail: "the resource was not found", Err: err}
+	case ent.IsConstraintError(err):
+		return &models.Error{Kind: models.ErrorKindConflict, Code: "conflict", Detail: "the request conflicts with the current state of the resource", Err: err}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return &models.Error{Kind: models.ErrorKindInternal, Code: "request_canceled", Detail: "the request was canceled before it completed", Err: err}
+	default:
+		return &models.Error{Kind: models.ErrorKindInternal, Code: "internal_error", Detail: "the request failed, quote the correlation id when reporting it", Err: err}
+	}
+}
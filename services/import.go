@@ -0,0 +1,423 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/importjob"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// importChunkSize is the number of rows upserted per statement, the job progress is saved after each chunk
+	importChunkSize = 100
+	maxImportLine   = 1 << 20
+)
+
+// ErrImportNotFound is returned when no import job has the requested id
+var ErrImportNotFound = models.NewError(models.ErrorKindNotFound, "import_not_found", "import not found")
+
+// importRow is a single product read from an import file
+type importRow struct {
+	line     int
+	sku      string
+	name     string
+	category string
+	price    int
+}
+
+// parseCSV reads a CSV file whose header names the sku, name, category and price columns in any order
+func parseCSV(r io.Reader) ([]importRow, []models.RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"sku", "name", "category", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("the csv header is missing the %s column", name)
+		}
+	}
+
+	var rows []importRow
+	var rowErrs []models.RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrs = append(rowErrs, models.RowError{Line: parseErr.StartLine, Errors: models.ValidationErrors{{Field: "row", Message: parseErr.Err.Error()}}})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read the csv file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := importRow{line: line, sku: value("sku"), name: value("name"), category: value("category")}
+		if priceStr := value("price"); priceStr != "" {
+			price, err := strconv.Atoi(priceStr)
+			if err != nil {
+				rowErrs = append(rowErrs, models.RowError{Line: line, SKU: row.sku, Errors: models.ValidationErrors{{Field: "price", Message: "must be a whole number"}}})
+				continue
+			}
+			row.price = price
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrs, nil
+}
+
+// parseNDJSON reads one JSON product per line, blank lines are skipped
+func parseNDJSON(r io.Reader) ([]importRow, []models.RowError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+
+	var rows []importRow
+	var rowErrs []models.RowError
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record struct {
+			SKU      string      `json:"sku"`
+			Name     string      `json:"name"`
+			Category string      `json:"category"`
+			Price    json.Number `json:"price"`
+		}
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			rowErrs = append(rowErrs, models.RowError{Line: line, Errors: models.ValidationErrors{{Field: "row", Message: "must be a valid JSON object"}}})
+			continue
+		}
+
+		row := importRow{line: line, sku: strings.TrimSpace(record.SKU), name: strings.TrimSpace(record.Name), category: strings.TrimSpace(record.Category)}
+		if record.Price != "" {
+			price, err := strconv.Atoi(record.Price.String())
+			if err != nil {
+				rowErrs = append(rowErrs, models.RowError{Line: line, SKU: row.sku, Errors: models.ValidationErrors{{Field: "price", Message: "must be a whole number"}}})
+				continue
+			}
+			row.price = price
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read the ndjson file: %w", err)
+	}
+	return rows, rowErrs, nil
+}
+
+// validateImportRows checks every row against the rules of the product endpoints and drops the invalid ones
+func validateImportRows(rows []importRow, categories map[string]int) ([]importRow, []models.RowError) {
+	valid := make([]importRow, 0, len(rows))
+	var rowErrs []models.RowError
+	firstLine := make(map[string]int, len(rows))
+
+	for _, row := range rows {
+		var errs models.ValidationErrors
+		switch {
+		case row.sku == "":
+			errs = append(errs, models.FieldError{Field: "sku", Message: "is required"})
+		case len(row.sku) > 64:
+			errs = append(errs, models.FieldError{Field: "sku", Message: "must be at most 64 characters"})
+		case firstLine[row.sku] > 0:
+			errs = append(errs, models.FieldError{Field: "sku", Message: fmt.Sprintf("is already used on line %d", firstLine[row.sku])})
+		}
+		switch {
+		case row.name == "":
+			errs = append(errs, models.FieldError{Field: "name", Message: "is required"})
+		case len([]rune(row.name)) > 255:
+			errs = append(errs, models.FieldError{Field: "name", Message: "must be at most 255 characters"})
+		}
+		switch _, ok := categories[row.category]; {
+		case row.category == "":
+			errs = append(errs, models.FieldError{Field: "category", Message: "is required"})
+		case !ok:
+			errs = append(errs, models.FieldError{Field: "category", Message: fmt.Sprintf("category %s does not exist", row.category)})
+		}
+		if row.price < 1 {
+			errs = append(errs, models.FieldError{Field: "price", Message: "must be at least 1"})
+		}
+
+		if row.sku != "" && firstLine[row.sku] == 0 {
+			firstLine[row.sku] = row.line
+		}
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, models.RowError{Line: row.line, SKU: row.sku, Errors: errs})
+			continue
+		}
+		valid = append(valid, row)
+	}
+	return valid, rowErrs
+}
+
+// upsertRows creates the rows whose sku is new and updates the others, it returns how many were created and updated
+func upsertRows(ctx context.Context, client *ent.Client, rows []importRow, categories map[string]int) (int, int, error) {
+	skus := make([]string, 0, len(rows))
+	for _, row := range rows {
+		skus = append(skus, row.sku)
+	}
+	existing, err := client.Product.Query().Where(product.SkuIn(skus...)).Count(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed counting existing products: %w", err)
+	}
+
+	builders := make([]*ent.ProductCreate, 0, len(rows))
+	for _, row := range rows {
+		builders = append(builders, client.Product.Create().
+			SetSku(row.sku).
+			SetName(row.name).
+			SetPrice(row.price).
+			SetCategoryID(categories[row.category]))
+	}
+
+	err = client.Product.CreateBulk(builders...).
+		OnConflictColumns(product.FieldSku).
+		Update(func(u *ent.ProductUpsert) {
+			u.UpdateName()
+			u.UpdatePrice()
+			u.UpdateUpdatedAt()
+			u.SetExcluded(product.CategoryColumn)
+		}).
+		Exec(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to upsert products: %w", err)
+	}
+	return len(rows) - existing, existing, nil
+}
+
+func importJobResponseFields(job *ent.ImportJob) *models.ImportJob {
+	errs := job.Errors
+	if errs == nil {
+		errs = []models.RowError{}
+	}
+	return &models.ImportJob{
+		ID:            job.ID,
+		Status:        job.Status.String(),
+		Mode:          job.Mode.String(),
+		Format:        job.Format,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		CreatedRows:   job.CreatedRows,
+		UpdatedRows:   job.UpdatedRows,
+		FailedRows:    job.FailedRows,
+		Errors:        errs,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
+
+// ImportProducts parses an upload and starts a background job that upserts its products by sku.
+// A file that can't be read at all is rejected right away, invalid rows are reported on the job.
+func (rs *RestService) ImportProducts(ctx context.Context, format, mode string, body io.Reader) (*models.ImportJob, error) {
+	var rows []importRow
+	var rowErrs []models.RowError
+	var err error
+
+	switch format {
+	case models.ImportFormatCSV:
+		rows, rowErrs, err = parseCSV(body)
+	case models.ImportFormatNDJSON:
+		rows, rowErrs, err = parseNDJSON(body)
+	default:
+		return nil, models.ValidationErrors{{Field: "format", Message: "must be one of csv, ndjson"}}
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return nil, &models.Error{
+			Kind:   models.ErrorKindTooLarge,
+			Code:   "file_too_large",
+			Detail: fmt.Sprintf("the file must be at most %d bytes", maxBytesErr.Limit),
+			Err:    err,
+		}
+	}
+	if err != nil {
+		return nil, models.ValidationErrors{{Field: "body", Message: err.Error()}}
+	}
+
+	job, err := rs.DB.ImportJob.Create().
+		SetMode(importjob.Mode(mode)).
+		SetFormat(format).
+		SetTotalRows(len(rows) + len(rowErrs)).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	rs.imports.Add(1)
+	go func() {
+		defer rs.imports.Done()
+		rs.runImport(context.Background(), job, rows, rowErrs)
+	}()
+
+	return importJobResponseFields(job), nil
+}
+
+// runImport validates and stores the rows of an import job, saving its progress as it goes
+func (rs *RestService) runImport(ctx context.Context, job *ent.ImportJob, rows []importRow, rowErrs []models.RowError) {
+	logger := log.WithField("import", job.ID)
+	var created, updated int
+
+	finish := func(status importjob.Status, rowErrs []models.RowError) {
+		slices.SortStableFunc(rowErrs, func(a, b models.RowError) int {
+			return cmp.Compare(a.Line, b.Line)
+		})
+		err := rs.DB.ImportJob.UpdateOne(job).
+			SetStatus(status).
+			SetProcessedRows(job.TotalRows).
+			SetCreatedRows(created).
+			SetUpdatedRows(updated).
+			SetFailedRows(len(rowErrs)).
+			SetErrors(rowErrs).
+			SetFinishedAt(time.Now()).
+			Exec(ctx)
+		if err != nil {
+			logger.WithError(err).Error("failed to save import result")
+		}
+		logger.Infof("import %s: %d created, %d updated, %d failed", status, created, updated, len(rowErrs))
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			logger.Errorf("import panicked: %v", v)
+			created, updated = 0, 0
+			finish(importjob.StatusFailed, append(rowErrs, models.RowError{Errors: models.ValidationErrors{{Field: "import", Message: "unexpected error"}}}))
+		}
+	}()
+
+	if err := rs.DB.ImportJob.UpdateOne(job).SetStatus(importjob.StatusRunning).Exec(ctx); err != nil {
+		logger.WithError(err).Error("failed to start import")
+	}
+
+	dbCategories, err := rs.DB.Category.Query().All(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch categories")
+		finish(importjob.StatusFailed, append(rowErrs, models.RowError{Errors: models.ValidationErrors{{Field: "import", Message: "failed to fetch categories"}}}))
+		return
+	}
+	categories := make(map[string]int, len(dbCategories))
+	for _, dbCategory := range dbCategories {
+		categories[dbCategory.Name] = dbCategory.ID
+	}
+
+	rows, invalid := validateImportRows(rows, categories)
+	rowErrs = append(rowErrs, invalid...)
+
+	progress := func(processed int) {
+		err := rs.DB.ImportJob.UpdateOne(job).
+			SetProcessedRows(processed).
+			SetCreatedRows(created).
+			SetUpdatedRows(updated).
+			Exec(ctx)
+		if err != nil {
+			logger.WithError(err).Warn("failed to save import progress")
+		}
+	}
+
+	if job.Mode == importjob.ModeAtomic {
+		// all or nothing, a single invalid row fails the whole import
+		if len(rowErrs) > 0 {
+			finish(importjob.StatusFailed, rowErrs)
+			return
+		}
+
+		err := rs.withTx(ctx, func(tx *ent.Tx) error {
+			for start := 0; start < len(rows); start += importChunkSize {
+				chunk := rows[start:min(start+importChunkSize, len(rows))]
+				c, u, err := upsertRows(ctx, tx.Client(), chunk, categories)
+				if err != nil {
+					return err
+				}
+				created, updated = created+c, updated+u
+				progress(start + len(chunk))
+			}
+			return nil
+		})
+		if err != nil {
+			logger.WithError(err).Error("atomic import rolled back")
+			created, updated = 0, 0
+			finish(importjob.StatusFailed, []models.RowError{{Errors: models.ValidationErrors{{Field: "import", Message: "failed to store the products, nothing was imported"}}}})
+			return
+		}
+		finish(importjob.StatusSucceeded, nil)
+		return
+	}
+
+	// best effort, a chunk that fails is retried row by row to find the rows at fault
+	processed := len(rowErrs)
+	for start := 0; start < len(rows); start += importChunkSize {
+		chunk := rows[start:min(start+importChunkSize, len(rows))]
+		c, u, err := upsertRows(ctx, rs.DB, chunk, categories)
+		if err != nil {
+			for _, row := range chunk {
+				c, u, err := upsertRows(ctx, rs.DB, []importRow{row}, categories)
+				if err != nil {
+					logger.WithError(err).Warnf("failed to import line %d", row.line)
+					rowErrs = append(rowErrs, models.RowError{Line: row.line, SKU: row.sku, Errors: models.ValidationErrors{{Field: "row", Message: "failed to store the product"}}})
+					continue
+				}
+				created, updated = created+c, updated+u
+			}
+		} else {
+			created, updated = created+c, updated+u
+		}
+		processed += len(chunk)
+		progress(processed)
+	}
+
+	status := importjob.StatusSucceeded
+	if len(rows) == 0 && len(rowErrs) > 0 {
+		status = importjob.StatusFailed
+	}
+	finish(status, rowErrs)
+}
+
+// FetchImport reports the progress of an import job
+func (rs *RestService) FetchImport(ctx context.Context, id int) (*models.ImportJob, error) {
+	job, err := rs.DB.ImportJob.Get(ctx, id)
+	if ent.IsNotFound(err) {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch import %d: %w", id, err)
+	}
+	return importJobResponseFields(job), nil
+}
+
+// WaitForImports blocks until the running import jobs are done, used on shutdown
+func (rs *RestService) WaitForImports() {
+	rs.imports.Wait()
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/tonymj76/mytheresa-test/models"
+	"slices"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events a subscriber may lag behind before it is dropped, the client reconnects
+// with Last-Event-ID and catches up from the buffer of the feed
+const subscriberBuffer = 64
+
+// PriceSubscription is a client of the price stream
+type PriceSubscription struct {
+	// Replay holds the buffered events after the Last-Event-ID of the client, oldest first
+	Replay []models.PriceChange
+	// Reset is set when the Last-Event-ID is no longer buffered or was never sent by this process, the client
+	// missed changes and should reload the prices
+	Reset bool
+	// LastEventID is the id of the newest event when the client subscribed
+	LastEventID uint64
+	// Events is closed when the subscriber falls too far behind or the feed is closed
+	Events <-chan models.PriceChange
+}
+
+// priceFeed fans the price changes found by the catalogue watcher out to the stream subscribers and keeps the last
+// ones in memory so a client that reconnects gets what it missed
+type priceFeed struct {
+	mu     sync.Mutex
+	size   int
+	events []models.PriceChange
+	// lastID starts at the time the process started in microseconds, the ids a client got from a previous
+	// process are then older than the buffer instead of colliding with the new ones
+	lastID      uint64
+	subscribers map[*priceSubscriber]struct{}
+	closed      bool
+	// unwatched is the number of changes the catalogue watcher let go by without any subscriber, seenUnwatched
+	// its value when the buffer was last known to be complete
+	unwatched     func() uint64
+	seenUnwatched uint64
+}
+
+type priceSubscriber struct {
+	categories []string
+	events     chan models.PriceChange
+}
+
+// WithPriceFeed publishes the price changes to the price stream and keeps the last size of them for the clients
+// that resume, a size of zero or less disables the stream. It watches the catalogue with ent hooks so it must come
+// after the option that sets the database
+func WithPriceFeed(size int) RestServiceConfiguration {
+	return func(rs *RestService) error {
+		if size <= 0 {
+			return nil
+		}
+		if rs.DB == nil {
+			return errors.New("the price feed needs a database, pass WithPriceFeed after the database option")
+		}
+
+		rs.priceFeed = &priceFeed{
+			size:        size,
+			lastID:      uint64(time.Now().UnixMicro()),
+			subscribers: map[*priceSubscriber]struct{}{},
+		}
+		rs.watchCatalogue(catalogueListener{
+			watching: rs.priceFeed.watching,
+			handle: func(events catalogueEvents) {
+				rs.priceFeed.publish(events.Changes)
+			},
+		})
+		rs.priceFeed.unwatched = rs.watcher.unwatched.Load
+		rs.priceFeed.seenUnwatched = rs.priceFeed.unwatched()
+		return nil
+	}
+}
+
+// SubscribePriceChanges streams the price changes of the products in the categories of the filter, or of every
+// product when there are none, until ctx is done
+func (rs *RestService) SubscribePriceChanges(ctx context.Context, filter models.PriceStreamFilter) (*PriceSubscription, error) {
+	if rs.priceFeed == nil {
+		return nil, ErrPriceStreamDisabled
+	}
+	return rs.priceFeed.subscribe(ctx, filter), nil
+}
+
+// ClosePriceFeed ends the open price streams, used on shutdown before the server waits for the connections to finish
+func (rs *RestService) ClosePriceFeed() {
+	if rs.priceFeed != nil {
+		rs.priceFeed.close()
+	}
+}
+
+func (pf *priceFeed) subscribe(ctx context.Context, filter models.PriceStreamFilter) *PriceSubscription {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	if unwatched := pf.unwatched(); unwatched != pf.seenUnwatched {
+		// prices may have changed while nobody was subscribed, the ids given out so far can't be resumed
+		pf.seenUnwatched = unwatched
+		pf.events = nil
+		pf.lastID++
+	}
+
+	sub := &priceSubscriber{categories: filter.Categories, events: make(chan models.PriceChange, subscriberBuffer)}
+	subscription := &PriceSubscription{LastEventID: pf.lastID, Events: sub.events}
+
+	if filter.LastEventID != 0 {
+		oldest := pf.lastID + 1
+		if len(pf.events) > 0 {
+			oldest = pf.events[0].ID
+		}
+		if filter.LastEventID > pf.lastID || filter.LastEventID < oldest-1 {
+			subscription.Reset = true
+		} else {
+			for _, event := range pf.events {
+				if event.ID > filter.LastEventID && event.InCategory(sub.categories) {
+					subscription.Replay = append(subscription.Replay, event)
+				}
+			}
+		}
+	}
+
+	if pf.closed {
+		close(sub.events)
+		return subscription
+	}
+	pf.subscribers[sub] = struct{}{}
+	context.AfterFunc(ctx, func() {
+		pf.mu.Lock()
+		defer pf.mu.Unlock()
+		pf.dropLocked(sub)
+	})
+	return subscription
+}
+
+// publish numbers the changes, buffers them and hands them to the subscribers of their category
+func (pf *priceFeed) publish(changes []models.PriceChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	if pf.closed {
+		return
+	}
+
+	for _, change := range changes {
+		pf.lastID++
+		change.ID = pf.lastID
+		pf.events = append(pf.events, change)
+
+		for sub := range pf.subscribers {
+			if !change.InCategory(sub.categories) {
+				continue
+			}
+			select {
+			case sub.events <- change:
+			default:
+				// a slow client would hold the others back, it resumes from the buffer when it reconnects
+				pf.dropLocked(sub)
+			}
+		}
+	}
+	if extra := len(pf.events) - pf.size; extra > 0 {
+		pf.events = slices.Delete(pf.events, 0, extra)
+	}
+}
+
+// watching reports whether a client is subscribed, the catalogue is only watched for them
+func (pf *priceFeed) watching() bool {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return len(pf.subscribers) > 0
+}
+
+func (pf *priceFeed) dropLocked(sub *priceSubscriber) {
+	if _, ok := pf.subscribers[sub]; ok {
+		delete(pf.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (pf *priceFeed) close() {
+	pf.mu.Lock()
+	if !pf.closed {
+		pf.closed = true
+		for sub := range pf.subscribers {
+			pf.dropLocked(sub)
+		}
+	}
+	pf.mu.Unlock()
+}
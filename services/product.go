@@ -1,104 +1,246 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"github.com/gin-gonic/gin"
 	"github.com/guregu/null/v5"
 	"github.com/tonymj76/mytheresa-test/ent"
 	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/predicate"
 	"github.com/tonymj76/mytheresa-test/ent/product"
 	"github.com/tonymj76/mytheresa-test/models"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"time"
 )
 
-// discountRecord is a map that store category name or sku with the percentage discount to be associated with it.
+// promotion is a percentage discount, it is only active between StartsAt and EndsAt when they are set
+type promotion struct {
+	Discount float64
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// active reports whether the promotion applies at the given time
+func (p promotion) active(at time.Time) bool {
+	return p.Discount > 0 &&
+		(p.StartsAt.IsZero() || !at.Before(p.StartsAt)) &&
+		(p.EndsAt.IsZero() || at.Before(p.EndsAt))
+}
+
+// discountRecord is a map that store category name or sku with the promotion to be associated with it.
 // for more scalability in production discountRecord will have its on table.
-var discountRecord = map[string]float64{
-	"boots":  0.30, // 30%
-	"000003": 0.15, // 15%
+var discountRecord = map[string]promotion{
+	"boots":  {Discount: 0.30}, // 30%
+	"000003": {Discount: 0.15}, // 15%
 }
 
 const CURRENCY = "EUR"
 
-func applyDiscount(epd *ent.Product) models.Product {
-	var discount float64
-	var pd models.Product
-	if value, ok := discountRecord[epd.Edges.Category.Name]; ok {
-		discount = value
+// activePromotion picks the highest discount active at the given time for the product category or sku
+func activePromotion(epd *ent.Product, at time.Time) (promotion, bool) {
+	var best promotion
+	for _, key := range []string{epd.Edges.Category.Name, epd.Sku} {
+		if value, ok := discountRecord[key]; ok && value.active(at) && value.Discount > best.Discount {
+			best = value
+		}
+	}
+	return best, best.Discount > 0
+}
+
+// PromotionVersion identifies the set of promotions active at the given time, it changes whenever a promotion
+// starts or ends so anything priced with them can be invalidated
+func PromotionVersion(at time.Time) string {
+	keys := make([]string, 0, len(discountRecord))
+	for key, value := range discountRecord {
+		if value.active(at) {
+			keys = append(keys, key)
+		}
 	}
+	slices.Sort(keys)
 
-	if value, ok := discountRecord[epd.Sku]; ok {
-		discount = max(discount, value)
+	h := fnv.New64a()
+	for _, key := range keys {
+		value := discountRecord[key]
+		fmt.Fprintf(h, "%s:%v:%d:%d;", key, value.Discount, value.StartsAt.UnixNano(), value.EndsAt.UnixNano())
 	}
-	if discount > 0 {
-		pd.Price.DiscountPercentage = null.StringFrom(fmt.Sprintf("%v%%", discount*100))
-		pd.Price.Final = int(float64(epd.Price) * (1 - discount))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// NextPromotionChange returns when the next promotion starts or ends after the given time, ok is false when no
+// promotion is scheduled to change
+func NextPromotionChange(at time.Time) (next time.Time, ok bool) {
+	for _, value := range discountRecord {
+		for _, bound := range []time.Time{value.StartsAt, value.EndsAt} {
+			if bound.After(at) && (!ok || bound.Before(next)) {
+				next, ok = bound, true
+			}
+		}
+	}
+	return next, ok
+}
+
+func applyDiscount(epd *ent.Product, at time.Time) models.Product {
+	var pd models.Product
+	if promo, ok := activePromotion(epd, at); ok {
+		pd.Price.DiscountPercentage = null.StringFrom(fmt.Sprintf("%v%%", promo.Discount*100))
+		pd.Price.Final = int(float64(epd.Price) * (1 - promo.Discount))
+		pd.Price.DiscountStartsAt = null.NewTime(promo.StartsAt, !promo.StartsAt.IsZero())
+		pd.Price.DiscountEndsAt = null.NewTime(promo.EndsAt, !promo.EndsAt.IsZero())
 	} else {
 		pd.Price.Final = epd.Price
 	}
 	return pd
 }
 
+// onSale matches the products that get a discount of at least minDiscount percent.
+// A discount applies when the category name or the SKU is in discountRecord, so this checks both
+// the same way applyDiscount does and lets the database do the pagination.
+func onSale(minDiscount int) predicate.Product {
+	now := time.Now()
+	var keys []string
+	for key, value := range discountRecord {
+		if value.active(now) && value.Discount*100 >= float64(minDiscount) {
+			keys = append(keys, key)
+		}
+	}
+	return product.Or(
+		product.SkuIn(keys...),
+		product.HasCategoryWith(category.NameIn(keys...)),
+	)
+}
+
 func applyResponseFields(epd *ent.Product) models.Product {
-	pd := applyDiscount(epd)
+	return priceAt(epd, time.Now())
+}
+
+// priceAt renders the product with the promotions active at the given time
+func priceAt(epd *ent.Product, at time.Time) models.Product {
+	pd := applyDiscount(epd, at)
 	pd.Price.Original = epd.Price
 	pd.Price.Currency = CURRENCY
 	pd.ID = epd.ID
 	pd.SKU = epd.Sku
 	pd.Name = epd.Name
+	pd.NameTranslations = epd.NameTranslations
 	pd.CreatedAt = epd.CreatedAt
 	pd.UpdatedAt = epd.UpdatedAt
 	pd.Category = epd.Edges.Category.Name
 	return pd
 }
 
-// FilterProduct help to filter product base on category or price less than the value provide
-func (rs *RestService) FilterProduct(c *gin.Context, cate string, priceLessThan, page, limit int) (*models.ProductsResponse, error) {
-	var dbProducts []*ent.Product
-	var dbError error
+// fieldColumns are the columns that render the top level fields of models.Product, besides the ones selectColumns
+// always reads
+var fieldColumns = map[string][]string{
+	"name":              {product.FieldName, product.FieldNameTranslations},
+	"name_translations": {product.FieldNameTranslations},
+	"price":             {product.FieldPrice},
+	"created_at":        {product.FieldCreatedAt},
+}
+
+// selectColumns returns the columns the fields need. The sku and the update time are always read since the
+// promotions and the etags depend on them, ent adds the id and the category key on its own
+func selectColumns(fields []string) []string {
+	columns := []string{product.FieldSku, product.FieldUpdatedAt}
+	for _, field := range fields {
+		columns = append(columns, fieldColumns[field]...)
+	}
+	return slices.Compact(slices.Sorted(slices.Values(columns)))
+}
+
+// FilterPredicates turns the filter into the predicates of the product query, every transport filters the
+// products with them so the listings stay consistent
+func FilterPredicates(filter models.ProductFilter) []predicate.Product {
+	var predicates []predicate.Product
+
+	// category filtering takes precedence over priceLessThan
+	switch {
+	case len(filter.Categories) > 0:
+		predicates = append(predicates, product.HasCategoryWith(category.NameIn(filter.Categories...)))
+	case filter.PriceLessThan > 0:
+		predicates = append(predicates, product.PriceLTE(filter.PriceLessThan))
+	}
+
+	if len(filter.SKUs) > 0 {
+		predicates = append(predicates, product.SkuIn(filter.SKUs...))
+	}
+
+	if filter.Search != "" {
+		predicates = append(predicates, productMatches(filter.Search))
+	}
+
+	if filter.OnSale || filter.MinDiscount > 0 {
+		predicates = append(predicates, onSale(filter.MinDiscount))
+	}
+
+	return predicates
+}
+
+// ProductPrice prices the product with the active promotion, the category is queried when it wasn't eager-loaded
+func ProductPrice(ctx context.Context, epd *ent.Product) (models.PriceData, error) {
+	cat, err := epd.Edges.CategoryOrErr()
+	if err != nil {
+		if cat, err = epd.QueryCategory().Only(ctx); err != nil {
+			return models.PriceData{}, fmt.Errorf("failed to fetch the category of product %s: %w", epd.Sku, err)
+		}
+	}
+
+	// work on a copy, the resolvers of a product may price it concurrently
+	priced := *epd
+	priced.Edges.Category = cat
+	return applyResponseFields(&priced).Price, nil
+}
+
+// FilterProduct help to filter product base on categories, skus, price less than the value provide, a full-text search
+// or the products on sale. The results are served from the product cache when it is enabled
+func (rs *RestService) FilterProduct(ctx context.Context, filter models.ProductFilter) (*models.ProductsResponse, error) {
+	if rs.productCache != nil {
+		return rs.productCache.get(ctx, filter, rs.filterProduct)
+	}
+	return rs.filterProduct(ctx, filter)
+}
+
+func (rs *RestService) filterProduct(ctx context.Context, filter models.ProductFilter) (*models.ProductsResponse, error) {
 	var products models.Products
 
 	// Calculate offset
-	offset := (page - 1) * limit
+	offset := (filter.Page - 1) * filter.Limit
+
+	query := rs.DB.Product.Query().Where(FilterPredicates(filter)...)
 
-	// Get total count of products
-	total, err := rs.DB.Product.Query().Count(c)
+	// Get total count of the filtered products
+	total, err := query.Clone().Count(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed counting products: %w", err)
 	}
 
 	// Calculate total pages
-	totalPages := (total + limit - 1) / limit
+	totalPages := (total + filter.Limit - 1) / filter.Limit
 
-	// Query products with pagination
-	switch {
-	case cate != "":
-		dbProducts, dbError = rs.DB.Product.Query().
-			WithCategory().
-			Where(product.HasCategoryWith(category.Name(cate))).
-			Limit(limit).
-			Offset(offset).
-			All(c)
-	case priceLessThan > 0:
-		dbProducts, dbError = rs.DB.Product.Query().WithCategory().
-			WithCategory().
-			Where(product.PriceLTE(priceLessThan)).
-			Limit(limit).
-			Offset(offset).
-			All(c)
-	default:
-		dbProducts, dbError = rs.DB.Product.Query().WithCategory().
-			WithCategory().
-			Limit(limit).
-			Offset(offset).
-			All(c)
+	if filter.Search != "" {
+		query.Order(byRelevance(filter.Search)).Modify(selectNameHighlight(filter.Search))
+	}
+	if len(filter.Fields) > 0 {
+		// Select narrows the columns of the query itself, the returned ProductSelect is only needed to scan
+		query.Select(selectColumns(filter.Fields)...)
 	}
 
-	if dbError != nil {
-		return nil, fmt.Errorf("failed to fetch products: %w", dbError)
+	// Query products with pagination
+	dbProducts, err := query.
+		WithCategory().
+		Order(ent.Asc(product.FieldID)).
+		Limit(filter.Limit).
+		Offset(offset).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
 	}
 
 	for _, dbProduct := range dbProducts {
-		products = append(products, applyResponseFields(dbProduct))
+		pd := applyResponseFields(dbProduct)
+		pd.Highlight = nameHighlight(dbProduct)
+		products = append(products, pd)
 	}
 
 	// Build response
@@ -106,11 +248,44 @@ func (rs *RestService) FilterProduct(c *gin.Context, cate string, priceLessThan,
 		Products: products,
 		Meta: models.Meta{
 			TotalRecords: total,
-			Page:         page,
+			Page:         filter.Page,
 			TotalPages:   totalPages,
-			Limit:        limit,
+			Limit:        filter.Limit,
 		},
 	}
 
 	return response, nil
 }
+
+// EachProductPage walks through every product in id order with keyset pagination, so the whole catalogue is
+// never loaded in memory, and hands each page of priced products to fn
+func (rs *RestService) EachProductPage(ctx context.Context, pageSize int, fn func(models.Products) error) error {
+	lastID := 0
+	for {
+		dbProducts, err := rs.DB.Product.Query().
+			Where(product.IDGT(lastID)).
+			WithCategory().
+			Order(ent.Asc(product.FieldID)).
+			Limit(pageSize).
+			All(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch products after id %d: %w", lastID, err)
+		}
+		if len(dbProducts) == 0 {
+			return nil
+		}
+
+		products := make(models.Products, 0, len(dbProducts))
+		for _, dbProduct := range dbProducts {
+			products = append(products, applyResponseFields(dbProduct))
+		}
+		if err := fn(products); err != nil {
+			return err
+		}
+
+		if len(dbProducts) < pageSize {
+			return nil
+		}
+		lastID = dbProducts[len(dbProducts)-1].ID
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/models"
+)
+
+// productBySKU loads a product together with its category, which applyResponseFields needs
+func (rs *RestService) productBySKU(ctx context.Context, sku string) (*ent.Product, error) {
+	epd, err := rs.DB.Product.Query().
+		Where(product.Sku(sku)).
+		WithCategory().
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product %s: %w", sku, err)
+	}
+	return epd, nil
+}
+
+// productCategory looks up the category a product is assigned to, an unknown name is a validation error
+func (rs *RestService) productCategory(ctx context.Context, name string) (*ent.Category, error) {
+	cate, err := categoryByName(ctx, rs.DB, name)
+	if errors.Is(err, ErrCategoryNotFound) {
+		return nil, models.ValidationErrors{{Field: "category", Message: fmt.Sprintf("category %s does not exist", name)}}
+	}
+	return cate, err
+}
+
+// CreateProduct adds a new product with the given sku
+func (rs *RestService) CreateProduct(ctx context.Context, sku string, input models.ProductInput) (*models.Product, error) {
+	cate, err := rs.productCategory(ctx, input.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = rs.DB.Product.Create().
+		SetSku(sku).
+		SetName(input.Name).
+		SetNameTranslations(input.NameTranslations).
+		SetPrice(input.Price).
+		SetCategory(cate).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrSKUConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product %s: %w", sku, err)
+	}
+
+	return rs.productResponse(ctx, sku)
+}
+
+// ReplaceProduct overwrites every editable field of an existing product
+func (rs *RestService) ReplaceProduct(ctx context.Context, sku string, input models.ProductInput) (*models.Product, error) {
+	translations := input.NameTranslations
+	if translations == nil {
+		// a patch leaves the nil translations untouched, a replacement removes them
+		translations = map[string]string{}
+	}
+	return rs.PatchProduct(ctx, sku, models.ProductPatch{
+		Name:             &input.Name,
+		NameTranslations: translations,
+		Category:         &input.Category,
+		Price:            &input.Price,
+	})
+}
+
+// PatchProduct updates only the fields that are set in the patch
+func (rs *RestService) PatchProduct(ctx context.Context, sku string, patch models.ProductPatch) (*models.Product, error) {
+	epd, err := rs.productBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	update := epd.Update()
+	if patch.Name != nil {
+		update.SetName(*patch.Name)
+	}
+	if patch.NameTranslations != nil {
+		update.SetNameTranslations(patch.NameTranslations)
+	}
+	if patch.Price != nil {
+		update.SetPrice(*patch.Price)
+	}
+	if patch.Category != nil {
+		cate, err := rs.productCategory(ctx, *patch.Category)
+		if err != nil {
+			return nil, err
+		}
+		update.SetCategory(cate)
+	}
+
+	if _, err := update.Save(ctx); err != nil {
+		return nil, fmt.Errorf("failed to update product %s: %w", sku, err)
+	}
+
+	return rs.productResponse(ctx, sku)
+}
+
+// DeleteProduct removes the product with the given sku
+func (rs *RestService) DeleteProduct(ctx context.Context, sku string) error {
+	deleted, err := rs.DB.Product.Delete().Where(product.Sku(sku)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete product %s: %w", sku, err)
+	}
+	if deleted == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// productResponse reloads a product after a write so the response carries the stored values
+func (rs *RestService) productResponse(ctx context.Context, sku string) (*models.Product, error) {
+	epd, err := rs.productBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	pd := applyResponseFields(epd)
+	return &pd, nil
+}
+
+// FetchProduct returns a single priced product, with the category details when includeCategory is set
+func (rs *RestService) FetchProduct(ctx context.Context, sku string, includeCategory bool) (*models.Product, error) {
+	epd, err := rs.productBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	pd := applyResponseFields(epd)
+	if includeCategory {
+		products, err := epd.Edges.Category.QueryProducts().Count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed counting products of category %s: %w", epd.Edges.Category.Name, err)
+		}
+		cd := categoryResponseFields(epd.Edges.Category, products)
+		pd.CategoryDetails = &cd
+	}
+	return &pd, nil
+}
+
+// BatchGetProducts prices many products with a single query, the products keep the order of skus
+// and the skus that don't exist are reported in NotFound
+func (rs *RestService) BatchGetProducts(ctx context.Context, skus []string) (*models.BatchGetResponse, error) {
+	dbProducts, err := rs.DB.Product.Query().
+		Where(product.SkuIn(skus...)).
+		WithCategory().
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+
+	bySKU := make(map[string]*ent.Product, len(dbProducts))
+	for _, dbProduct := range dbProducts {
+		bySKU[dbProduct.Sku] = dbProduct
+	}
+
+	response := &models.BatchGetResponse{
+		Products: make(models.Products, 0, len(dbProducts)),
+		NotFound: make([]string, 0),
+	}
+	seen := make(map[string]bool, len(skus))
+	for _, sku := range skus {
+		if seen[sku] {
+			continue
+		}
+		seen[sku] = true
+
+		if dbProduct, ok := bySKU[sku]; ok {
+			response.Products = append(response.Products, applyResponseFields(dbProduct))
+		} else {
+			response.NotFound = append(response.NotFound, sku)
+		}
+	}
+	return response, nil
+}
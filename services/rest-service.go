@@ -1,14 +1,28 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"github.com/tonymj76/mytheresa-test/ent"
 	"github.com/tonymj76/mytheresa-test/storage"
+	"sync"
 )
 
 type RestServiceConfiguration func(rs *RestService) error
 
 type RestService struct {
 	DB *ent.Client
+
+	// imports tracks the import jobs running in the background
+	imports sync.WaitGroup
+	// productCache holds the recent FilterProduct results, it is nil when the cache is disabled
+	productCache *productCache
+	// priceFeed publishes the price changes to the price stream, it is nil when the stream is disabled
+	priceFeed *priceFeed
+	// watcher finds the catalogue events for the price feed and the webhooks, it is nil when neither is enabled
+	watcher *catalogueWatcher
+	// webhooks delivers the catalogue events to the subscribed webhooks, it is nil when they are disabled
+	webhooks *webhookDispatcher
 }
 
 func NewRestService(cfgs ...RestServiceConfiguration) (*RestService, error) {
@@ -34,3 +48,41 @@ func WithDBSetup() RestServiceConfiguration {
 	db, err := storage.NewDB("")
 	return WithCustomDB(db, err)
 }
+
+// Close stops watching the catalogue and delivering the webhooks, used on shutdown once the server stopped. The
+// pending deliveries are stored so they are sent after a restart
+func (rs *RestService) Close() {
+	rs.ClosePriceFeed()
+	if rs.watcher != nil {
+		rs.watcher.stop()
+	}
+	if rs.webhooks != nil {
+		rs.webhooks.stop()
+	}
+}
+
+// withTx runs fn inside a transaction that is rolled back when fn fails
+func (rs *RestService) withTx(ctx context.Context, fn func(tx *ent.Tx) error) error {
+	tx, err := rs.DB.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed starting transaction: %w", err)
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			_ = tx.Rollback()
+			panic(v)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: rolling back transaction: %v", err, rerr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed committing transaction: %w", err)
+	}
+	return nil
+}
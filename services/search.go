@@ -0,0 +1,82 @@
+package services
+
+import (
+	"entgo.io/ent/dialect/sql"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/predicate"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+)
+
+const (
+	// searchVectorColumn is the generated tsvector column created by storage.Migrate
+	searchVectorColumn = "search_vector"
+	// nameHighlightColumn is the alias of the ts_headline expression selected alongside a product
+	nameHighlightColumn = "name_highlight"
+	// categoryRankWeight lowers the relevance of a category match compared to a product name match
+	categoryRankWeight = 0.5
+)
+
+// writeTSQuery writes the tsquery parsed from the free text the shopper typed.
+// websearch_to_tsquery never fails on user input, so "leather boots" or `"ankle boots" -suede` are both fine.
+func writeTSQuery(b *sql.Builder, q string) {
+	b.WriteString("websearch_to_tsquery('english', ").Arg(q).WriteString(")")
+}
+
+// productMatches matches products whose name, or whose category name and description, contain the search terms
+func productMatches(q string) predicate.Product {
+	return func(s *sql.Selector) {
+		s.Where(sql.P(func(b *sql.Builder) {
+			b.WriteString("(").WriteString(s.C(searchVectorColumn)).WriteString(" @@ ")
+			writeTSQuery(b, q)
+			b.WriteString(" OR ").WriteString(s.C(product.CategoryColumn)).WriteString(" IN (SELECT ").
+				WriteString(category.FieldID).WriteString(" FROM ").WriteString(category.Table).
+				WriteString(" WHERE ").WriteString(searchVectorColumn).WriteString(" @@ ")
+			writeTSQuery(b, q)
+			b.WriteString("))")
+		}))
+	}
+}
+
+// byRelevance orders the products by how well they match the search terms, best match first
+func byRelevance(q string) product.OrderOption {
+	return func(s *sql.Selector) {
+		s.OrderExprFunc(func(b *sql.Builder) {
+			b.WriteString("ts_rank(").WriteString(s.C(searchVectorColumn)).WriteString(", ")
+			writeTSQuery(b, q)
+			b.WriteString(") + coalesce((SELECT ts_rank(").WriteString(searchVectorColumn).WriteString(", ")
+			writeTSQuery(b, q)
+			b.WriteString(") FROM ").WriteString(category.Table).
+				WriteString(" WHERE ").WriteString(category.FieldID).WriteString(" = ").WriteString(s.C(product.CategoryColumn)).
+				WriteString(fmt.Sprintf("), 0) * %v DESC", categoryRankWeight))
+		})
+	}
+}
+
+// selectNameHighlight adds the product name with the matched terms wrapped in <mark> tags to the selection
+func selectNameHighlight(q string) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		s.AppendSelectExprAs(sql.ExprFunc(func(b *sql.Builder) {
+			b.WriteString("ts_headline('english', ").WriteString(s.C(product.FieldName)).WriteString(", ")
+			writeTSQuery(b, q)
+			b.WriteString(", 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')")
+		}), nameHighlightColumn)
+	}
+}
+
+// nameHighlight reads the highlighted name selected by selectNameHighlight, if any
+func nameHighlight(epd *ent.Product) null.String {
+	value, err := epd.Value(nameHighlightColumn)
+	if err != nil {
+		return null.String{}
+	}
+	switch v := value.(type) {
+	case string:
+		return null.StringFrom(v)
+	case []byte:
+		return null.StringFrom(string(v))
+	}
+	return null.String{}
+}
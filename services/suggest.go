@@ -0,0 +1,91 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"entgo.io/ent/dialect/sql"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/models"
+	"slices"
+)
+
+// similarityScoreColumn is the alias of the word_similarity expression selected for every suggestion
+const similarityScoreColumn = "score"
+
+// suggestionRow is what is scanned from the products and categories tables
+type suggestionRow struct {
+	Name  string  `json:"name"`
+	Sku   string  `json:"sku"`
+	Score float64 `json:"score"`
+}
+
+// similarTo selects the rows whose name contains a word similar to the prefix, best match first.
+// The <% operator uses pg_trgm.word_similarity_threshold so "ashk" still finds "Ashlington".
+func similarTo(prefix string, limit int, columns ...string) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		// products and categories both keep the display name in the "name" column
+		nameColumn := s.C(product.FieldName)
+		selected := make([]string, 0, len(columns))
+		for _, column := range columns {
+			selected = append(selected, s.C(column))
+		}
+		s.Select(selected...).
+			AppendSelectExprAs(sql.ExprFunc(func(b *sql.Builder) {
+				b.WriteString("word_similarity(").Arg(prefix).WriteString(", ").WriteString(nameColumn).WriteString(")")
+			}), similarityScoreColumn).
+			Where(sql.P(func(b *sql.Builder) {
+				b.Arg(prefix).WriteString(" <% ").WriteString(nameColumn)
+			})).
+			OrderExpr(sql.Expr(similarityScoreColumn + " DESC")).
+			Limit(limit)
+	}
+}
+
+// SuggestProducts returns up to limit product and category names similar to what the shopper typed so far
+func (rs *RestService) SuggestProducts(ctx context.Context, prefix string, limit int) (models.Suggestions, error) {
+	var productRows, categoryRows []suggestionRow
+
+	err := rs.DB.Product.Query().
+		Modify(similarTo(prefix, limit, product.FieldName, product.FieldSku)).
+		Scan(ctx, &productRows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest products: %w", err)
+	}
+
+	err = rs.DB.Category.Query().
+		Modify(similarTo(prefix, limit, category.FieldName)).
+		Scan(ctx, &categoryRows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest categories: %w", err)
+	}
+
+	suggestions := make(models.Suggestions, 0, len(productRows)+len(categoryRows))
+	for _, row := range categoryRows {
+		suggestions = append(suggestions, models.Suggestion{
+			Type:  models.SuggestionTypeCategory,
+			Text:  row.Name,
+			Score: row.Score,
+		})
+	}
+	for _, row := range productRows {
+		suggestions = append(suggestions, models.Suggestion{
+			Type:  models.SuggestionTypeProduct,
+			Text:  row.Name,
+			SKU:   null.StringFrom(row.Sku),
+			Score: row.Score,
+		})
+	}
+
+	// categories come first on a tie since they narrow the search the most
+	slices.SortStableFunc(suggestions, func(a, b models.Suggestion) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/category"
+	"github.com/tonymj76/mytheresa-test/ent/hook"
+	"github.com/tonymj76/mytheresa-test/ent/predicate"
+	"github.com/tonymj76/mytheresa-test/ent/product"
+	"github.com/tonymj76/mytheresa-test/models"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// catalogueEvents is what a committed mutation, or a promotion starting or ending, changed in the catalogue
+type catalogueEvents struct {
+	Created []models.Product
+	Changes []models.PriceChange
+}
+
+// catalogueListener receives the catalogue events, such as the price feed and the webhooks
+type catalogueListener struct {
+	// watching reports whether the listener wants the events right now, the products are only priced before and
+	// after a mutation when a listener does
+	watching func() bool
+	// handle is called once the mutation committed, it must not block the request
+	handle func(catalogueEvents)
+}
+
+// catalogueWatcher finds the products created and the prices changed by the mutations made through the ent client
+// and by the promotions, and hands them to the listeners
+type catalogueWatcher struct {
+	mu        sync.RWMutex
+	listeners []catalogueListener
+	// unwatched counts the mutations and promotions that went by while no listener was watching, the price feed
+	// compares it to tell a resuming client it missed changes
+	unwatched atomic.Uint64
+
+	// ctx is canceled when the watcher stops, the hooks then leave the mutations alone
+	ctx    context.Context
+	cancel context.CancelFunc
+	// done is closed once the promotion watcher returned
+	done chan struct{}
+}
+
+// watchCatalogue hands the events of the catalogue to the listener, the first call registers the ent hooks and
+// starts watching the promotions
+func (rs *RestService) watchCatalogue(listener catalogueListener) {
+	if rs.watcher == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		rs.watcher = &catalogueWatcher{ctx: ctx, cancel: cancel, done: make(chan struct{})}
+		rs.DB.Product.Use(hook.On(rs.watcher.productHook, ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne))
+		// a renamed category gains or loses the promotion of its name
+		rs.DB.Category.Use(hook.On(rs.watcher.categoryHook, ent.OpUpdate|ent.OpUpdateOne))
+		go rs.watcher.watchPromotions(rs.DB)
+	}
+
+	rs.watcher.mu.Lock()
+	defer rs.watcher.mu.Unlock()
+	rs.watcher.listeners = append(rs.watcher.listeners, listener)
+}
+
+func (cw *catalogueWatcher) emit(events catalogueEvents) {
+	if (len(events.Created) == 0 && len(events.Changes) == 0) || cw.ctx.Err() != nil {
+		return
+	}
+
+	cw.mu.RLock()
+	listeners := cw.listeners
+	cw.mu.RUnlock()
+	for _, listener := range listeners {
+		listener.handle(events)
+	}
+}
+
+// skip reports whether no listener is watching, the mutation is then left alone and counted as unwatched. A
+// listener that starts watching meanwhile either sees the count change or gets the events
+func (cw *catalogueWatcher) skip() bool {
+	if cw.watching() {
+		return false
+	}
+	cw.unwatched.Add(1)
+	return !cw.watching()
+}
+
+func (cw *catalogueWatcher) watching() bool {
+	cw.mu.RLock()
+	defer cw.mu.RUnlock()
+	return slices.ContainsFunc(cw.listeners, func(listener catalogueListener) bool {
+		return listener.watching()
+	})
+}
+
+func (cw *catalogueWatcher) stop() {
+	cw.cancel()
+	<-cw.done
+}
+
+// productHook watches the products a mutation creates or updates. A created sku that already exists is an upsert
+// of an import, it is a price change instead of a new product
+func (cw *catalogueWatcher) productHook(next ent.Mutator) ent.Mutator {
+	return hook.ProductFunc(func(ctx context.Context, m *ent.ProductMutation) (ent.Value, error) {
+		if cw.ctx.Err() != nil {
+			return next.Mutate(ctx, m)
+		}
+		if m.Op().Is(ent.OpCreate) {
+			sku, ok := m.Sku()
+			if !ok || cw.skip() {
+				return next.Mutate(ctx, m)
+			}
+			return cw.compare(ctx, m, m.Client(), product.Sku(sku), next)
+		}
+
+		_, price := m.Price()
+		_, addedPrice := m.AddedPrice()
+		_, sku := m.Sku()
+		_, moved := m.CategoryID()
+		if (!price && !addedPrice && !sku && !moved && !m.CategoryCleared()) || cw.skip() {
+			return next.Mutate(ctx, m)
+		}
+		ids, err := m.IDs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the updated products: %w", err)
+		}
+		return cw.compare(ctx, m, m.Client(), product.IDIn(ids...), next)
+	})
+}
+
+// categoryHook watches the products of the renamed categories
+func (cw *catalogueWatcher) categoryHook(next ent.Mutator) ent.Mutator {
+	return hook.CategoryFunc(func(ctx context.Context, m *ent.CategoryMutation) (ent.Value, error) {
+		if _, ok := m.Name(); !ok || cw.ctx.Err() != nil || cw.skip() {
+			return next.Mutate(ctx, m)
+		}
+		ids, err := m.IDs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the updated categories: %w", err)
+		}
+		return cw.compare(ctx, m, m.Client(), product.HasCategoryWith(category.IDIn(ids...)), next)
+	})
+}
+
+// compare prices the products matching where before and after the mutation and emits the new ones and the ones
+// whose price changed, once the transaction of the mutation commits
+func (cw *catalogueWatcher) compare(ctx context.Context, m ent.Mutation, client *ent.Client, where predicate.Product, next ent.Mutator) (ent.Value, error) {
+	at := time.Now()
+	before, err := pricedProducts(ctx, client, where, at)
+	if err != nil {
+		return nil, err
+	}
+	value, err := next.Mutate(ctx, m)
+	if err != nil {
+		return value, err
+	}
+	after, err := pricedProducts(ctx, client, where, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var events catalogueEvents
+	for _, sku := range slices.Sorted(maps.Keys(after)) {
+		previous, ok := before[sku]
+		if !ok {
+			events.Created = append(events.Created, after[sku])
+			continue
+		}
+		if change, ok := priceChange(previous, after[sku], models.PriceChangeUpdate, at); ok {
+			events.Changes = append(events.Changes, change)
+		}
+	}
+	if len(events.Created) == 0 && len(events.Changes) == 0 {
+		return value, nil
+	}
+
+	if txm, ok := m.(interface{ Tx() (*ent.Tx, error) }); ok {
+		if tx, err := txm.Tx(); err == nil {
+			// the listeners must not see the changes of a transaction that is rolled back
+			tx.OnCommit(func(next ent.Committer) ent.Committer {
+				return ent.CommitFunc(func(ctx context.Context, tx *ent.Tx) error {
+					err := next.Commit(ctx, tx)
+					if err == nil {
+						cw.emit(events)
+					}
+					return err
+				})
+			})
+			return value, nil
+		}
+	}
+	cw.emit(events)
+	return value, nil
+}
+
+// pricedProducts prices the products matching where by sku, with the given client which may belong to a transaction
+func pricedProducts(ctx context.Context, client *ent.Client, where predicate.Product, at time.Time) (map[string]models.Product, error) {
+	dbProducts, err := client.Product.Query().Where(where).WithCategory().All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price the changed products: %w", err)
+	}
+	priced := make(map[string]models.Product, len(dbProducts))
+	for _, dbProduct := range dbProducts {
+		priced[dbProduct.Sku] = priceAt(dbProduct, at)
+	}
+	return priced, nil
+}
+
+// priceChange builds the event of a product whose original price, final price or discount changed
+func priceChange(previous, current models.Product, reason string, at time.Time) (models.PriceChange, bool) {
+	if previous.Price.Original == current.Price.Original &&
+		previous.Price.Final == current.Price.Final &&
+		previous.Price.DiscountPercentage == current.Price.DiscountPercentage {
+		return models.PriceChange{}, false
+	}
+
+	change := models.PriceChange{
+		SKU:       current.SKU,
+		Category:  current.Category,
+		Reason:    reason,
+		Previous:  previous.Price,
+		Current:   current.Price,
+		ChangedAt: at,
+	}
+	if previous.Category != current.Category {
+		change.PreviousCategory = previous.Category
+	}
+	return change, true
+}
+
+// watchPromotions wakes up whenever a promotion starts or ends and emits the prices it changed
+func (cw *catalogueWatcher) watchPromotions(client *ent.Client) {
+	defer close(cw.done)
+
+	from := time.Now()
+	for {
+		at, ok := NextPromotionChange(from)
+		if !ok {
+			return
+		}
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-cw.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := cw.emitPromotionChanges(cw.ctx, client, at); err != nil {
+			log.WithError(err).Error("failed to emit the price changes of the promotions")
+		}
+		from = at
+	}
+}
+
+// emitPromotionChanges emits the prices of the products whose promotion starts or ends at the given time
+func (cw *catalogueWatcher) emitPromotionChanges(ctx context.Context, client *ent.Client, at time.Time) error {
+	before := at.Add(-time.Nanosecond)
+	var keys []string
+	for key, value := range discountRecord {
+		if value.active(before) != value.active(at) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 || cw.skip() {
+		return nil
+	}
+
+	dbProducts, err := client.Product.Query().
+		Where(product.Or(
+			product.SkuIn(keys...),
+			product.HasCategoryWith(category.NameIn(keys...)),
+		)).
+		WithCategory().
+		Order(ent.Asc(product.FieldID)).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the products of the promotions: %w", err)
+	}
+
+	var events catalogueEvents
+	for _, dbProduct := range dbProducts {
+		if change, ok := priceChange(priceAt(dbProduct, before), priceAt(dbProduct, at), models.PriceChangePromotion, at); ok {
+			events.Changes = append(events.Changes, change)
+		}
+	}
+	cw.emit(events)
+	return nil
+}
@@ -0,0 +1,756 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/tonymj76/mytheresa-test/ent"
+	"github.com/tonymj76/mytheresa-test/ent/webhook"
+	"github.com/tonymj76/mytheresa-test/ent/webhookdelivery"
+	"github.com/tonymj76/mytheresa-test/models"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader holds sha256= and the hex HMAC-SHA256 of the timestamp, a dot and the body, keyed with
+	// the secret of the webhook, see SignWebhookPayload
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookTimestampHeader is the unix time of the attempt, receivers should reject the old ones to stop replays
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	webhookUserAgent = "mytheresa-webhooks/1.0"
+	// webhookBatchSize is how many due deliveries are loaded at once, they are sent by webhookWorkers at a time
+	webhookBatchSize = 20
+	webhookWorkers   = 4
+	// webhookPollInterval is the longest the dispatcher sleeps, it picks up the deliveries queued by other instances
+	// and the webhooks they created
+	webhookPollInterval = time.Minute
+	// webhookQueueSize is how many catalogue events wait to be stored as deliveries before new ones are dropped
+	webhookQueueSize = 1024
+	// webhookStoreRetry is the first delay before storing the deliveries again, it doubles up to webhookPollInterval
+	webhookStoreRetry = time.Second
+)
+
+var (
+	// ErrWebhooksDisabled is returned by the webhook methods of a service started without WithWebhooks
+	ErrWebhooksDisabled = models.NewError(models.ErrorKindUnavailable, "webhooks_disabled", "the webhooks are disabled")
+	// ErrWebhookNotFound is returned when no webhook has the requested id
+	ErrWebhookNotFound = models.NewError(models.ErrorKindNotFound, "webhook_not_found", "webhook not found")
+	// ErrDeliveryNotFound is returned when the webhook has no delivery with the requested id
+	ErrDeliveryNotFound = models.NewError(models.ErrorKindNotFound, "delivery_not_found", "delivery not found")
+	// ErrDeliveryPending is returned when redelivering a delivery that is still being retried
+	ErrDeliveryPending = models.NewError(models.ErrorKindConflict, "delivery_pending", "the delivery is still pending")
+)
+
+// WebhookOptions tunes the deliveries, the zero values pick the defaults
+type WebhookOptions struct {
+	// Timeout bounds every attempt, 10 seconds by default
+	Timeout time.Duration
+	// MaxAttempts is how many times a delivery is sent before it is dead, 8 by default
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it doubles after every failed attempt up to MaxBackoff.
+	// 30 seconds and an hour by default
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// AllowPrivateTargets lets the webhooks point at loopback and private addresses, only meant for the tests
+	// that deliver to a local receiver
+	AllowPrivateTargets bool
+}
+
+// webhookDispatcher stores a delivery for every catalogue event a webhook subscribed to and sends the due ones
+// in the background. The deliveries live in the database so the retries survive a restart
+type webhookDispatcher struct {
+	db     *ent.Client
+	opts   WebhookOptions
+	client *http.Client
+	// wake is signaled when deliveries are due now
+	wake chan struct{}
+	// queue holds the catalogue events until they are stored, away from the request that made them
+	queue chan catalogueEvents
+	// subscribed is set when a webhook exists, the catalogue isn't watched for the webhooks otherwise
+	subscribed atomic.Bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// running waits for the sender and the storer to return
+	running sync.WaitGroup
+}
+
+// WithWebhooks delivers the catalogue events to the subscribed webhooks. It watches the catalogue with ent hooks
+// so it must come after the option that sets the database
+func WithWebhooks(opts WebhookOptions) RestServiceConfiguration {
+	return func(rs *RestService) error {
+		if rs.DB == nil {
+			return errors.New("the webhooks need a database, pass WithWebhooks after the database option")
+		}
+		if opts.Timeout <= 0 {
+			opts.Timeout = 10 * time.Second
+		}
+		if opts.MaxAttempts <= 0 {
+			opts.MaxAttempts = 8
+		}
+		if opts.Backoff <= 0 {
+			opts.Backoff = 30 * time.Second
+		}
+		if opts.MaxBackoff <= 0 {
+			opts.MaxBackoff = time.Hour
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		rs.webhooks = &webhookDispatcher{
+			db:     rs.DB,
+			opts:   opts,
+			client: webhookClient(opts),
+			wake:   make(chan struct{}, 1),
+			queue:  make(chan catalogueEvents, webhookQueueSize),
+			ctx:    ctx,
+			cancel: cancel,
+		}
+		rs.webhooks.refreshSubscribed(ctx)
+		rs.watchCatalogue(catalogueListener{
+			watching: rs.webhooks.subscribed.Load,
+			handle:   rs.webhooks.enqueue,
+		})
+		rs.webhooks.running.Add(2)
+		go rs.webhooks.run()
+		go rs.webhooks.storeQueued()
+		return nil
+	}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of the timestamp, a dot and the payload, the receivers compute
+// it again with their secret to check the X-Webhook-Signature header
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomToken returns a prefix followed by n random bytes in hex
+func randomToken(prefix string, n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a random token: %w", err)
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
+
+func webhookResponseFields(ew *ent.Webhook) models.Webhook {
+	return models.Webhook{
+		ID:        ew.ID,
+		URL:       ew.URL,
+		Events:    ew.Events,
+		CreatedAt: ew.CreatedAt,
+		UpdatedAt: ew.UpdatedAt,
+	}
+}
+
+func deliveryResponseFields(ed *ent.WebhookDelivery) models.WebhookDelivery {
+	attempts := ed.Attempts
+	if attempts == nil {
+		attempts = []models.DeliveryAttempt{}
+	}
+	return models.WebhookDelivery{
+		ID:            ed.ID,
+		WebhookID:     ed.WebhookID,
+		EventID:       ed.EventID,
+		Event:         ed.Event,
+		Status:        ed.Status.String(),
+		Attempts:      attempts,
+		AttemptsLeft:  ed.AttemptsLeft,
+		NextAttemptAt: ed.NextAttemptAt,
+		DeliveredAt:   ed.DeliveredAt,
+		Payload:       ed.Payload,
+		CreatedAt:     ed.CreatedAt,
+		UpdatedAt:     ed.UpdatedAt,
+	}
+}
+
+// CreateWebhook subscribes the url to the events, the secret is generated when the input has none and is only
+// returned here
+func (rs *RestService) CreateWebhook(ctx context.Context, input models.WebhookInput) (*models.Webhook, error) {
+	if rs.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	if err := rs.webhooks.checkTarget(ctx, input.URL); err != nil {
+		return nil, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		var err error
+		if secret, err = randomToken("whsec_", 24); err != nil {
+			return nil, err
+		}
+	}
+
+	ew, err := rs.DB.Webhook.Create().
+		SetURL(input.URL).
+		SetSecret(secret).
+		SetEvents(slices.Compact(slices.Sorted(slices.Values(input.Events)))).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	rs.webhooks.subscribed.Store(true)
+
+	wh := webhookResponseFields(ew)
+	wh.Secret = secret
+	return &wh, nil
+}
+
+// FetchWebhooks lists every webhook without its secret
+func (rs *RestService) FetchWebhooks(ctx context.Context) (models.Webhooks, error) {
+	if rs.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+
+	dbWebhooks, err := rs.DB.Webhook.Query().Order(ent.Asc(webhook.FieldID)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+	webhooks := make(models.Webhooks, 0, len(dbWebhooks))
+	for _, ew := range dbWebhooks {
+		webhooks = append(webhooks, webhookResponseFields(ew))
+	}
+	return webhooks, nil
+}
+
+// FetchWebhook returns a webhook without its secret
+func (rs *RestService) FetchWebhook(ctx context.Context, id int) (*models.Webhook, error) {
+	if rs.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+
+	ew, err := rs.DB.Webhook.Get(ctx, id)
+	if ent.IsNotFound(err) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook %d: %w", id, err)
+	}
+	wh := webhookResponseFields(ew)
+	return &wh, nil
+}
+
+// DeleteWebhook unsubscribes a webhook together with its deliveries
+func (rs *RestService) DeleteWebhook(ctx context.Context, id int) error {
+	if rs.webhooks == nil {
+		return ErrWebhooksDisabled
+	}
+
+	err := rs.withTx(ctx, func(tx *ent.Tx) error {
+		if _, err := tx.WebhookDelivery.Delete().Where(webhookdelivery.WebhookID(id)).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete the deliveries of webhook %d: %w", id, err)
+		}
+		err := tx.Webhook.DeleteOneID(id).Exec(ctx)
+		if ent.IsNotFound(err) {
+			return ErrWebhookNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete webhook %d: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	rs.webhooks.refreshSubscribed(ctx)
+	return nil
+}
+
+// FetchWebhookDeliveries returns a page of the delivery log, newest first. The dead deliveries of every webhook
+// make up the dead-letter list
+func (rs *RestService) FetchWebhookDeliveries(ctx context.Context, filter models.DeliveryFilter) (*models.WebhookDeliveries, error) {
+	if rs.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+
+	query := rs.DB.WebhookDelivery.Query()
+	if filter.WebhookID != 0 {
+		exists, err := rs.DB.Webhook.Query().Where(webhook.ID(filter.WebhookID)).Exist(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch webhook %d: %w", filter.WebhookID, err)
+		}
+		if !exists {
+			return nil, ErrWebhookNotFound
+		}
+		query.Where(webhookdelivery.WebhookID(filter.WebhookID))
+	}
+	if filter.Status != "" {
+		query.Where(webhookdelivery.StatusEQ(webhookdelivery.Status(filter.Status)))
+	}
+
+	total, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed counting deliveries: %w", err)
+	}
+
+	dbDeliveries, err := query.
+		Order(ent.Desc(webhookdelivery.FieldCreatedAt), ent.Desc(webhookdelivery.FieldID)).
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deliveries: %w", err)
+	}
+
+	resp := &models.WebhookDeliveries{
+		Deliveries: make([]models.WebhookDelivery, 0, len(dbDeliveries)),
+		Meta: models.Meta{
+			TotalRecords: total,
+			Page:         filter.Page,
+			TotalPages:   (total + filter.Limit - 1) / filter.Limit,
+			Limit:        filter.Limit,
+		},
+	}
+	for _, ed := range dbDeliveries {
+		resp.Deliveries = append(resp.Deliveries, deliveryResponseFields(ed))
+	}
+	return resp, nil
+}
+
+// RedeliverWebhook queues a dead or succeeded delivery again with a fresh number of attempts, its log is kept
+func (rs *RestService) RedeliverWebhook(ctx context.Context, webhookID, deliveryID int) (*models.WebhookDelivery, error) {
+	if rs.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+
+	ed, err := rs.DB.WebhookDelivery.Query().
+		Where(webhookdelivery.ID(deliveryID), webhookdelivery.WebhookID(webhookID)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delivery %d: %w", deliveryID, err)
+	}
+	if ed.Status == webhookdelivery.StatusPending {
+		return nil, ErrDeliveryPending
+	}
+
+	ed, err = ed.Update().
+		SetStatus(webhookdelivery.StatusPending).
+		SetAttemptsLeft(rs.webhooks.opts.MaxAttempts).
+		SetNextAttemptAt(time.Now()).
+		ClearDeliveredAt().
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeliver delivery %d: %w", deliveryID, err)
+	}
+	rs.webhooks.signal()
+
+	wd := deliveryResponseFields(ed)
+	return &wd, nil
+}
+
+// enqueue hands the events of a committed mutation to storeQueued without waiting for the database
+func (wd *webhookDispatcher) enqueue(events catalogueEvents) {
+	select {
+	case wd.queue <- events:
+	default:
+		log.WithFields(log.Fields{"created": len(events.Created), "changes": len(events.Changes)}).
+			Error("the webhook queue is full, dropping the catalogue events")
+	}
+}
+
+// storeQueued stores the queued events as deliveries, retrying while the database is unavailable. The events
+// still queued on shutdown get a last attempt
+func (wd *webhookDispatcher) storeQueued() {
+	defer wd.running.Done()
+
+	for {
+		select {
+		case <-wd.ctx.Done():
+			for {
+				select {
+				case events := <-wd.queue:
+					ctx, cancel := context.WithTimeout(context.WithoutCancel(wd.ctx), 10*time.Second)
+					if err := wd.store(ctx, events); err != nil {
+						log.WithError(err).Error("failed to store the webhook deliveries on shutdown")
+					}
+					cancel()
+				default:
+					return
+				}
+			}
+		case events := <-wd.queue:
+			for delay := webhookStoreRetry; ; delay = min(2*delay, webhookPollInterval) {
+				ctx, cancel := context.WithTimeout(wd.ctx, 10*time.Second)
+				err := wd.store(ctx, events)
+				cancel()
+				if err == nil || wd.ctx.Err() != nil {
+					break
+				}
+				log.WithError(err).WithField("retry", delay).Error("failed to store the webhook deliveries")
+				timer := time.NewTimer(delay)
+				select {
+				case <-wd.ctx.Done():
+				case <-timer.C:
+				}
+				timer.Stop()
+			}
+		}
+	}
+}
+
+// refreshSubscribed reads whether a webhook exists, when it can't be read the catalogue is watched to be safe
+func (wd *webhookDispatcher) refreshSubscribed(ctx context.Context) {
+	exists, err := wd.db.Webhook.Query().Exist(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("failed to check for webhooks")
+		}
+		exists = true
+	}
+	wd.subscribed.Store(exists)
+}
+
+func (wd *webhookDispatcher) store(ctx context.Context, events catalogueEvents) error {
+	webhooks, err := wd.db.Webhook.Query().All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	var payloads []models.WebhookEvent
+	for _, pd := range events.Created {
+		payloads = append(payloads, models.WebhookEvent{Type: models.WebhookProductCreated, Data: pd})
+	}
+	for _, change := range events.Changes {
+		payloads = append(payloads, models.WebhookEvent{Type: models.WebhookProductRepriced, Data: change})
+		if !change.Previous.DiscountPercentage.Valid && change.Current.DiscountPercentage.Valid {
+			payloads = append(payloads, models.WebhookEvent{Type: models.WebhookProductOnSale, Data: change})
+		}
+	}
+
+	now := time.Now()
+	var builders []*ent.WebhookDeliveryCreate
+	for _, event := range payloads {
+		event.CreatedAt = now
+		if event.ID, err = randomToken("evt_", 16); err != nil {
+			return err
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode the %s event: %w", event.Type, err)
+		}
+
+		for _, ew := range webhooks {
+			if !slices.Contains(ew.Events, event.Type) {
+				continue
+			}
+			builders = append(builders, wd.db.WebhookDelivery.Create().
+				SetWebhookID(ew.ID).
+				SetEventID(event.ID).
+				SetEvent(event.Type).
+				SetPayload(payload).
+				SetAttemptsLeft(wd.opts.MaxAttempts).
+				SetNextAttemptAt(now))
+		}
+	}
+	if len(builders) == 0 {
+		return nil
+	}
+
+	if err := wd.db.WebhookDelivery.CreateBulk(builders...).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store the deliveries: %w", err)
+	}
+	wd.signal()
+	return nil
+}
+
+func (wd *webhookDispatcher) signal() {
+	select {
+	case wd.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (wd *webhookDispatcher) stop() {
+	wd.cancel()
+	wd.running.Wait()
+}
+
+// run sends the due deliveries and sleeps until the next one is due, a new delivery wakes it up
+func (wd *webhookDispatcher) run() {
+	defer wd.running.Done()
+
+	for {
+		// the webhooks may have been created or deleted by another instance
+		wd.refreshSubscribed(wd.ctx)
+		wait := webhookPollInterval
+		next, err := wd.deliverDue(wd.ctx)
+		switch {
+		case wd.ctx.Err() != nil:
+			return
+		case err != nil:
+			log.WithError(err).Error("failed to send the webhook deliveries")
+		case next != nil:
+			wait = min(wait, time.Until(*next))
+		}
+
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-wd.ctx.Done():
+			timer.Stop()
+			return
+		case <-wd.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// deliverDue sends the pending deliveries that are due and returns when the next one is due, nil when none is left
+func (wd *webhookDispatcher) deliverDue(ctx context.Context) (*time.Time, error) {
+	for {
+		due, err := wd.db.WebhookDelivery.Query().
+			Where(
+				webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+				webhookdelivery.NextAttemptAtLTE(time.Now()),
+			).
+			WithWebhook().
+			Order(ent.Asc(webhookdelivery.FieldNextAttemptAt), ent.Asc(webhookdelivery.FieldID)).
+			Limit(webhookBatchSize).
+			All(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the due deliveries: %w", err)
+		}
+
+		var wg sync.WaitGroup
+		workers := make(chan struct{}, webhookWorkers)
+		for _, ed := range due {
+			workers <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-workers
+					wg.Done()
+				}()
+				wd.deliver(ctx, ed)
+			}()
+		}
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if len(due) < webhookBatchSize {
+			break
+		}
+	}
+
+	next, err := wd.db.WebhookDelivery.Query().
+		Where(webhookdelivery.StatusEQ(webhookdelivery.StatusPending)).
+		Order(ent.Asc(webhookdelivery.FieldNextAttemptAt)).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the next delivery: %w", err)
+	}
+	return next.NextAttemptAt, nil
+}
+
+// deliver sends one attempt of the delivery and records its outcome. The delivery is leased first by moving its
+// next attempt past the timeout of the client, so another instance doesn't send it meanwhile and a crash only
+// delays it
+func (wd *webhookDispatcher) deliver(ctx context.Context, ed *ent.WebhookDelivery) {
+	logger := log.WithFields(log.Fields{"webhook": ed.WebhookID, "delivery": ed.ID, "event": ed.Event})
+
+	leased, err := wd.db.WebhookDelivery.Update().
+		Where(
+			webhookdelivery.ID(ed.ID),
+			webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+			webhookdelivery.NextAttemptAtEQ(*ed.NextAttemptAt),
+		).
+		SetNextAttemptAt(time.Now().Add(wd.opts.Timeout + time.Minute)).
+		Save(ctx)
+	if err != nil || leased == 0 {
+		if err != nil {
+			logger.WithError(err).Error("failed to lease the delivery")
+		}
+		return
+	}
+
+	attempt := wd.send(ctx, ed)
+	if ctx.Err() != nil {
+		// shutting down, the lease runs out and the delivery is sent again
+		return
+	}
+
+	attempts := append(ed.Attempts, attempt)
+	update := wd.db.WebhookDelivery.UpdateOneID(ed.ID).SetAttempts(attempts)
+	switch left := ed.AttemptsLeft - 1; {
+	case attempt.Error == "":
+		update.SetStatus(webhookdelivery.StatusSucceeded).SetDeliveredAt(attempt.At).ClearNextAttemptAt()
+	case left <= 0:
+		logger.WithField("error", attempt.Error).Warn("webhook delivery is dead")
+		update.SetStatus(webhookdelivery.StatusDead).SetAttemptsLeft(0).ClearNextAttemptAt()
+	default:
+		update.SetAttemptsLeft(left).SetNextAttemptAt(time.Now().Add(wd.backoff(wd.opts.MaxAttempts - left)))
+	}
+	if err := update.Exec(context.WithoutCancel(ctx)); err != nil {
+		logger.WithError(err).Error("failed to record the delivery attempt")
+	}
+}
+
+// send posts the payload to the webhook with its signature
+func (wd *webhookDispatcher) send(ctx context.Context, ed *ent.WebhookDelivery) models.DeliveryAttempt {
+	attempt := models.DeliveryAttempt{At: time.Now()}
+	timestamp := strconv.FormatInt(attempt.At.Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ed.Edges.Webhook.URL, bytes.NewReader(ed.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(WebhookEventHeader, ed.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(ed.ID))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(ed.Edges.Webhook.Secret, timestamp, ed.Payload))
+
+	resp, err := wd.client.Do(req)
+	attempt.DurationMS = time.Since(attempt.At).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	// drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("the receiver answered %s", resp.Status)
+	}
+	return attempt
+}
+
+// backoff is the delay after the given number of failed attempts, it doubles every time up to MaxBackoff
+func (wd *webhookDispatcher) backoff(failures int) time.Duration {
+	delay := wd.opts.Backoff
+	for range failures - 1 {
+		if delay >= wd.opts.MaxBackoff/2 {
+			return wd.opts.MaxBackoff
+		}
+		delay *= 2
+	}
+	return min(delay, wd.opts.MaxBackoff)
+}
+
+// webhookClient sends the deliveries. Unless the private targets are allowed it refuses to connect to the
+// loopback, private and link-local addresses, checked on the address actually dialed so a host resolving to
+// another address after the webhook was created is caught too, and it never follows redirects
+func webhookClient(opts WebhookOptions) *http.Client {
+	dialer := &net.Dialer{Timeout: opts.Timeout, KeepAlive: 30 * time.Second}
+	if !opts.AllowPrivateTargets {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("failed to parse the webhook address %s: %w", address, err)
+			}
+			if !publicAddr(addrPort.Addr()) {
+				return fmt.Errorf("the webhook address %s is not public", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: opts.Timeout,
+		// no proxy, it would dial on our behalf out of reach of the check
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: webhookWorkers,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		// a redirect is a failed attempt, following it could lead anywhere
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// reservedPrefixes are the ranges that aren't reachable on the internet beyond what netip reports
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// publicAddr reports whether addr can be the address of a partner, the loopback, private, link-local such as the
+// 169.254.169.254 metadata service, multicast and reserved ones can't
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTarget rejects the urls whose host resolves to an address that isn't public, the client checks the address
+// again on every delivery
+func (wd *webhookDispatcher) checkTarget(ctx context.Context, rawURL string) error {
+	if wd.opts.AllowPrivateTargets {
+		return nil
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return models.ValidationErrors{{Field: "url", Message: "must be an http or https url"}}
+	}
+
+	host := target.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicAddr(addr) {
+			return models.ValidationErrors{{Field: "url", Message: "must not point to a loopback, private or link-local address"}}
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return models.ValidationErrors{{Field: "url", Message: "must have a host that resolves"}}
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return models.ValidationErrors{{Field: "url", Message: "must not point to a loopback, private or link-local address"}}
+		}
+	}
+	return nil
+}
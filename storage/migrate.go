@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tonymj76/mytheresa-test/ent"
+)
+
+// migrations holds the raw SQL that ent's auto migration can't express, such as
+// generated columns and GIN indexes. Every statement must be idempotent because
+// it runs on each boot right after client.Schema.Create.
+var migrations = []string{
+	// full-text search over the product name
+	`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('english', coalesce(name, ''))) STORED`,
+	`CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING GIN (search_vector)`,
+
+	// full-text search over the category name and description
+	`ALTER TABLE categories ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('english', coalesce(name, '') || ' ' || coalesce(description, ''))) STORED`,
+	`CREATE INDEX IF NOT EXISTS categories_search_vector_idx ON categories USING GIN (search_vector)`,
+
+	// trigram indexes backing the typo-tolerant autocomplete
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS products_name_trgm_idx ON products USING GIN (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS categories_name_trgm_idx ON categories USING GIN (name gin_trgm_ops)`,
+}
+
+// Migrate applies the raw SQL migrations on top of the ent schema
+func Migrate(ctx context.Context, client *ent.Client) error {
+	for _, stmt := range migrations {
+		if _, err := client.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed running migration %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
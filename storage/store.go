@@ -28,5 +28,9 @@ func NewDB(link string) (*ent.Client, error) {
 		defer client.Close()
 		log.Fatalf("failed creating schema resources: %v", err)
 	}
+	if err := Migrate(context.Background(), client); err != nil {
+		defer client.Close()
+		log.Fatalf("failed running sql migrations: %v", err)
+	}
 	return client, nil
 }